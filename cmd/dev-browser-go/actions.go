@@ -2,7 +2,6 @@ package main
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +14,7 @@ import (
 func newActionsCmd() *cobra.Command {
 	var callsArg string
 	var pageName string
+	var varsArg []string
 
 	cmd := &cobra.Command{
 		Use:   "actions",
@@ -29,10 +29,18 @@ func newActionsCmd() *cobra.Command {
 				}
 				raw = string(b)
 			}
-			var calls []map[string]interface{}
-			if err := json.Unmarshal([]byte(raw), &calls); err != nil {
-				return errors.New("invalid JSON for --calls/stdin")
+			calls, vars, err := parseActionsInput(raw)
+			if err != nil {
+				return err
+			}
+			for _, kv := range varsArg {
+				name, value, ok := strings.Cut(kv, "=")
+				if !ok || strings.TrimSpace(name) == "" {
+					return usageErrorf("--var must be name=value")
+				}
+				vars[strings.TrimSpace(name)] = value
 			}
+
 			ws, tid, err := devbrowser.EnsurePage(globalOpts.profile, globalOpts.headless, pageName, globalOpts.window)
 			if err != nil {
 				return err
@@ -44,25 +52,61 @@ func newActionsCmd() *cobra.Command {
 			defer browser.Close()
 			defer pw.Stop()
 
-			res, err := devbrowser.RunActions(page, calls, devbrowser.ArtifactDir(globalOpts.profile))
-			if err != nil {
-				return err
+			res, runErr := devbrowser.RunActions(page, calls, devbrowser.ActionsOptions{
+				CallOptions: callOptions(pageName),
+				Vars:        vars,
+			})
+			if runErr != nil {
+				// Partial results travel in the error details so json output
+				// stays a single document.
+				te := devbrowser.ClassifyError(runErr)
+				if te.Details == nil {
+					te.Details = map[string]interface{}{}
+				}
+				te.Details["results"] = res.Results
+				if globalOpts.output == "json" {
+					return te
+				}
 			}
 			output := map[string]any{"results": res.Results}
 			if res.Snapshot != "" {
 				output["snapshot"] = res.Snapshot
 			}
+			if res.FailedStep >= 0 {
+				output["failed_step"] = res.FailedStep
+			}
 			out, err := devbrowser.WriteOutput(globalOpts.profile, globalOpts.output, output, globalOpts.outPath)
 			if err != nil {
 				return err
 			}
 			fmt.Println(out)
-			return nil
+			return runErr
 		},
 	}
 
-	cmd.Flags().StringVar(&callsArg, "calls", "", "JSON calls array (or stdin)")
+	cmd.Flags().StringVar(&callsArg, "calls", "", "JSON calls array, or {\"vars\": {...}, \"steps\": [...]} (or stdin)")
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringArrayVar(&varsArg, "var", nil, "Variable for ${vars.name} as name=value (repeatable)")
 
 	return cmd
 }
+
+// parseActionsInput accepts a plain calls array or an object with vars and
+// steps.
+func parseActionsInput(raw string) ([]map[string]interface{}, map[string]interface{}, error) {
+	var calls []map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &calls); err == nil {
+		return calls, map[string]interface{}{}, nil
+	}
+	var doc struct {
+		Vars  map[string]interface{}   `json:"vars"`
+		Steps []map[string]interface{} `json:"steps"`
+	}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil || doc.Steps == nil {
+		return nil, nil, usageErrorf("invalid JSON for --calls/stdin")
+	}
+	if doc.Vars == nil {
+		doc.Vars = map[string]interface{}{}
+	}
+	return doc.Steps, doc.Vars, nil
+}
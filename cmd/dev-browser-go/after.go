@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// afterFlags add --snapshot-after and an optional wait to interaction
+// commands so the result carries the page state the action led to.
+type afterFlags struct {
+	snapshot     bool
+	snap         snapshotFlags
+	waitStrategy string
+	waitText     string
+	waitSelector string
+	waitURL      string
+	waitTimeout  int
+}
+
+func (f *afterFlags) bind(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.snapshot, "snapshot-after", false, "Return a fresh snapshot after the action (accepts the snapshot flags)")
+	f.snap.bind(cmd)
+	cmd.Flags().StringVar(&f.waitStrategy, "wait-after", "", "Wait strategy to run after the action: playwright|perf|network|stable")
+	cmd.Flags().StringVar(&f.waitText, "wait-text", "", "Wait for visible text after the action")
+	cmd.Flags().StringVar(&f.waitSelector, "wait-selector", "", "Wait for CSS selector to be visible after the action")
+	cmd.Flags().StringVar(&f.waitURL, "wait-url-matches", "", "Wait for page URL to match regex after the action")
+	cmd.Flags().IntVar(&f.waitTimeout, "wait-timeout-ms", 10_000, "Timeout for the post-action wait")
+}
+
+func (f *afterFlags) apply(cmd *cobra.Command, payload map[string]interface{}) error {
+	if err := f.snap.validate(cmd); err != nil {
+		return err
+	}
+	wait := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"strategy":    f.waitStrategy,
+		"text":        f.waitText,
+		"selector":    f.waitSelector,
+		"url_matches": f.waitURL,
+	} {
+		if value != "" {
+			wait[key] = value
+		}
+	}
+	if len(wait) > 0 {
+		wait["timeout_ms"] = f.waitTimeout
+		payload["wait_after"] = wait
+	}
+	if f.snapshot {
+		payload["snapshot_after"] = f.snap.payload(cmd)
+	}
+	return nil
+}
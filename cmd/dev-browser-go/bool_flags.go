@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -17,6 +16,15 @@ var boolFlagRules = []boolFlagRule{
 	{name: "include-headings", hasNo: true},
 	{name: "full-page", hasNo: true},
 	{name: "annotate-refs", hasNo: false},
+	{name: "expand-spans", hasNo: true},
+	{name: "bypass-cache", hasNo: false},
+	{name: "report-effects", hasNo: false},
+	{name: "snapshot-after", hasNo: false},
+	{name: "clear", hasNo: false},
+	{name: "start", hasNo: false},
+	{name: "with-locators", hasNo: false},
+	{name: "exact", hasNo: false},
+	{name: "listeners", hasNo: true},
 }
 
 func rejectBoolEqualsArgs(args []string) error {
@@ -37,9 +45,9 @@ func rejectBoolEqualsArgs(args []string) error {
 				continue
 			}
 			if rule.hasNo {
-				return fmt.Errorf("use --%s or --no-%s (omit =true/false)", rule.name, rule.name)
+				return usageErrorf("use --%s or --no-%s (omit =true/false)", rule.name, rule.name)
 			}
-			return fmt.Errorf("use --%s (omit =true/false)", rule.name)
+			return usageErrorf("use --%s (omit =true/false)", rule.name)
 		}
 	}
 	return nil
@@ -48,7 +56,7 @@ func rejectBoolEqualsArgs(args []string) error {
 func applyNoFlag(cmd *cobra.Command, name string) error {
 	noName := "no-" + name
 	if cmd.Flags().Changed(noName) && cmd.Flags().Changed(name) {
-		return fmt.Errorf("use --%s or --%s, not both", name, noName)
+		return usageErrorf("use --%s or --%s, not both", name, noName)
 	}
 	if !cmd.Flags().Changed(noName) {
 		return nil
@@ -1,18 +1,12 @@
 package main
 
 import (
-	"errors"
-	"strings"
-
 	"github.com/spf13/cobra"
 )
 
 func newBoundsCmd() *cobra.Command {
 	var pageName string
-	var selector string
-	var ariaRole string
-	var ariaName string
-	var nth int
+	var target targetFlags
 	var timeout int
 
 	cmd := &cobra.Command{
@@ -20,34 +14,25 @@ func newBoundsCmd() *cobra.Command {
 		Short: "Get element bounding box",
 		Args:  maxArgs(1, "too many arguments"),
 		RunE: func(_ *cobra.Command, args []string) error {
-			if len(args) > 0 && strings.TrimSpace(selector) == "" {
-				selector = args[0]
-			}
-			if strings.TrimSpace(selector) == "" && strings.TrimSpace(ariaRole) == "" {
-				return errors.New("selector or --aria-role required")
+			if len(args) > 0 && target.selector == "" {
+				target.selector = args[0]
 			}
 			payload := map[string]interface{}{
-				"nth":        nth,
 				"timeout_ms": timeout,
 			}
-			if strings.TrimSpace(selector) != "" {
-				payload["selector"] = selector
-			}
-			if strings.TrimSpace(ariaRole) != "" {
-				payload["aria_role"] = ariaRole
+			ok, err := target.apply(payload)
+			if err != nil {
+				return err
 			}
-			if strings.TrimSpace(ariaName) != "" {
-				payload["aria_name"] = ariaName
+			if !ok {
+				return usageErrorf("selector or a target flag required")
 			}
 			return runWithPage(pageName, "bounds", payload)
 		},
 	}
 
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
-	cmd.Flags().StringVar(&selector, "selector", "", "CSS selector")
-	cmd.Flags().StringVar(&ariaRole, "aria-role", "", "ARIA role")
-	cmd.Flags().StringVar(&ariaName, "aria-name", "", "ARIA name")
-	cmd.Flags().IntVar(&nth, "nth", 1, "Nth match (1-based)")
+	target.bind(cmd)
 	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "Timeout ms")
 
 	return cmd
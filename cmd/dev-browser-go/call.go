@@ -2,7 +2,6 @@ package main
 
 import (
 	"encoding/json"
-	"errors"
 
 	"github.com/spf13/cobra"
 )
@@ -18,7 +17,7 @@ func newCallCmd() *cobra.Command {
 		RunE: func(_ *cobra.Command, args []string) error {
 			argMap := map[string]interface{}{}
 			if err := json.Unmarshal([]byte(argsJSON), &argMap); err != nil {
-				return errors.New("invalid JSON for --args")
+				return usageErrorf("invalid JSON for --args")
 			}
 			return runWithPage(pageName, args[0], argMap)
 		},
@@ -6,23 +6,42 @@ import (
 
 func newClickRefCmd() *cobra.Command {
 	var pageName string
+	var effects effectsFlags
+	var after afterFlags
 	var timeout int
+	var target targetFlags
 
 	cmd := &cobra.Command{
-		Use:   "click-ref <ref>",
-		Short: "Click element by ref",
-		Args:  requireArgs(1, "ref required"),
-		RunE: func(_ *cobra.Command, args []string) error {
+		Use:   "click-ref [ref]",
+		Short: "Click element by ref (or any target flag)",
+		Args:  maxArgs(1, "click-ref takes one ref"),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			payload := map[string]interface{}{
-				"ref":        args[0],
 				"timeout_ms": timeout,
 			}
+			if len(args) == 1 {
+				target.ref = args[0]
+			}
+			ok, err := target.apply(payload)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return usageErrorf("ref or a target flag required")
+			}
+			effects.apply(payload)
+			if err := after.apply(cmd, payload); err != nil {
+				return err
+			}
 			return runWithPage(pageName, "click_ref", payload)
 		},
 	}
 
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
 	cmd.Flags().IntVar(&timeout, "timeout-ms", 15_000, "Timeout ms")
+	target.bind(cmd, "ref")
+	effects.bind(cmd)
+	after.bind(cmd)
 
 	return cmd
 }
@@ -15,6 +15,7 @@ func newConsoleCmd() *cobra.Command {
 	var pageName string
 	var since int64
 	var limit int
+	var maxTokens int
 	var levels []string
 
 	cmd := &cobra.Command{
@@ -23,10 +24,13 @@ func newConsoleCmd() *cobra.Command {
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if since < 0 {
-				return fmt.Errorf("--since must be >= 0")
+				return usageErrorf("--since must be >= 0")
 			}
 			if limit < 0 {
-				return fmt.Errorf("--limit must be >= 0")
+				return usageErrorf("--limit must be >= 0")
+			}
+			if maxTokens < 0 {
+				return usageErrorf("--max-tokens must be >= 0")
 			}
 			base, err := startDaemonIfNeeded()
 			if err != nil {
@@ -43,6 +47,9 @@ func newConsoleCmd() *cobra.Command {
 			if since > 0 {
 				query.Set("since", strconv.FormatInt(since, 10))
 			}
+			if maxTokens > 0 {
+				query.Set("max_tokens", strconv.Itoa(maxTokens))
+			}
 			if encoded := query.Encode(); encoded != "" {
 				endpoint += "?" + encoded
 			}
@@ -65,6 +72,7 @@ func newConsoleCmd() *cobra.Command {
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
 	cmd.Flags().Int64Var(&since, "since", 0, "Only return entries with id > since")
 	cmd.Flags().IntVar(&limit, "limit", 200, "Max entries")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Approximate token budget (0 = off)")
 	cmd.Flags().StringArrayVar(&levels, "level", nil, "Log level (repeatable: debug,info,warning,error,all)")
 
 	return cmd
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type effectsFlags struct {
+	report   bool
+	settleMs int
+}
+
+func (f *effectsFlags) bind(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.report, "report-effects", false, "Report navigation, popups, dialogs, console errors, requests and focus changes caused by the action")
+	cmd.Flags().IntVar(&f.settleMs, "settle-ms", 500, "How long to keep watching for effects after the action")
+}
+
+func (f *effectsFlags) apply(payload map[string]interface{}) {
+	if !f.report {
+		return
+	}
+	payload["report_effects"] = true
+	payload["settle_ms"] = f.settleMs
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	devbrowser "github.com/joshp123/dev-browser-go/internal/devbrowser"
+)
+
+func usageErrorf(format string, a ...any) error {
+	return devbrowser.NewToolError(devbrowser.ErrInvalidArgument, fmt.Errorf(format, a...), nil)
+}
+
+// reportError prints err and returns the exit code for its class. With
+// --output json the error is written to stdout as {ok:false, error:{...}} so
+// callers can parse success and failure the same way.
+func reportError(err error) int {
+	te := devbrowser.ClassifyError(err)
+	if globalOpts.output == "json" {
+		enc, encErr := json.MarshalIndent(map[string]any{"ok": false, "error": te}, "", "  ")
+		if encErr == nil {
+			fmt.Println(string(enc))
+			return devbrowser.ExitCode(te.Code)
+		}
+	}
+	fmt.Fprintln(os.Stderr, err)
+	return devbrowser.ExitCode(te.Code)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newExpectCmd() *cobra.Command {
+	var pageName string
+	var urlMatches string
+	var titleContains string
+	var textVisible string
+	var target targetFlags
+	var state string
+	var count int
+	var noConsoleErrors bool
+	var since int
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "expect",
+		Short: "Assert page state, polling until it holds",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			payload := map[string]interface{}{
+				"timeout_ms": timeout,
+			}
+			for key, value := range map[string]string{
+				"url_matches":    urlMatches,
+				"title_contains": titleContains,
+				"text_visible":   textVisible,
+				"state":          state,
+			} {
+				if value != "" {
+					payload[key] = value
+				}
+			}
+			if _, err := target.apply(payload); err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("count") {
+				payload["count"] = count
+			}
+			if noConsoleErrors {
+				payload["no_console_errors"] = true
+				payload["since"] = since
+			}
+			return runWithPage(pageName, "expect", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringVar(&urlMatches, "url-matches", "", "Page URL matches regex")
+	cmd.Flags().StringVar(&titleContains, "title-contains", "", "Page title contains text")
+	cmd.Flags().StringVar(&textVisible, "text-visible", "", "Text is visible")
+	target.bind(cmd)
+	cmd.Flags().StringVar(&state, "state", "", "Element state: visible|hidden|checked|unchecked|enabled|disabled|expanded|collapsed|editable|focused (default visible)")
+	cmd.Flags().IntVar(&count, "count", 0, "Exact number of elements matching the target")
+	cmd.Flags().BoolVar(&noConsoleErrors, "no-console-errors", false, "No console errors logged after --since")
+	cmd.Flags().IntVar(&since, "since", 0, "Console entry id to check after (from console or --report-effects)")
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "How long to poll before failing")
+
+	return cmd
+}
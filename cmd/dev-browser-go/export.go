@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	devbrowser "github.com/joshp123/dev-browser-go/internal/devbrowser"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	var pageName string
+	var lang string
+	var filePath string
+	var name string
+	var clear bool
+	var start bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the calls made on a page as a Playwright test (TypeScript or Go)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if lang != "ts" && lang != "go" {
+				return usageErrorf("--lang must be ts or go")
+			}
+			logPath := devbrowser.SessionLogPath(globalOpts.profile, pageName)
+			if start {
+				if err := devbrowser.StartSessionLog(logPath); err != nil {
+					return err
+				}
+				out, err := devbrowser.WriteOutput(globalOpts.profile, globalOpts.output, map[string]any{
+					"page":    pageName,
+					"logging": true,
+				}, globalOpts.outPath)
+				if err != nil {
+					return err
+				}
+				fmt.Println(out)
+				return nil
+			}
+			entries, err := devbrowser.ReadSessionLog(logPath)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				return usageErrorf("no calls recorded for page '%s' (run export --start first)", pageName)
+			}
+			if name == "" {
+				name = pageName
+			}
+			src, skipped, err := devbrowser.ExportPlaywright(entries, lang, name)
+			if err != nil {
+				return err
+			}
+
+			defaultName := fmt.Sprintf("export-%d.spec.ts", devbrowser.NowMS())
+			if lang == "go" {
+				defaultName = fmt.Sprintf("export_%d_test.go", devbrowser.NowMS())
+			}
+			path, err := devbrowser.SafeArtifactPath(devbrowser.ArtifactDir(globalOpts.profile), filePath, defaultName)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+				return err
+			}
+			if clear {
+				if err := os.Remove(logPath); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+
+			out, err := devbrowser.WriteOutput(globalOpts.profile, globalOpts.output, map[string]any{
+				"path":    path,
+				"lang":    lang,
+				"calls":   len(entries),
+				"skipped": skipped,
+			}, globalOpts.outPath)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringVar(&lang, "lang", "ts", "Test flavor: ts (@playwright/test) or go (playwright-go)")
+	cmd.Flags().StringVar(&filePath, "file", "", "Write the test to this path (default: artifact dir)")
+	cmd.Flags().StringVar(&name, "name", "", "Test name (default: page name)")
+	cmd.Flags().BoolVar(&start, "start", false, "Start logging calls on the page for a later export")
+	cmd.Flags().BoolVar(&clear, "clear", false, "Stop logging after exporting")
+
+	return cmd
+}
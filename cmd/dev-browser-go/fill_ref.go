@@ -6,17 +6,38 @@ import (
 
 func newFillRefCmd() *cobra.Command {
 	var pageName string
+	var effects effectsFlags
+	var after afterFlags
 	var timeout int
+	var strategy string
+	var target targetFlags
 
 	cmd := &cobra.Command{
-		Use:   "fill-ref <ref> <text>",
-		Short: "Fill input by ref",
-		Args:  requireArgs(2, "ref and text required"),
-		RunE: func(_ *cobra.Command, args []string) error {
+		Use:   "fill-ref [ref] <text>",
+		Short: "Fill input by ref (or any target flag)",
+		Args:  maxArgs(2, "ref and text required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			payload := map[string]interface{}{
-				"ref":        args[0],
-				"text":       args[1],
 				"timeout_ms": timeout,
+				"strategy":   strategy,
+			}
+			if len(args) == 0 {
+				return usageErrorf("text required")
+			}
+			if len(args) == 2 {
+				target.ref = args[0]
+			}
+			payload["text"] = args[len(args)-1]
+			ok, err := target.apply(payload)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return usageErrorf("ref or a target flag required")
+			}
+			effects.apply(payload)
+			if err := after.apply(cmd, payload); err != nil {
+				return err
 			}
 			return runWithPage(pageName, "fill_ref", payload)
 		},
@@ -24,6 +45,10 @@ func newFillRefCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
 	cmd.Flags().IntVar(&timeout, "timeout-ms", 15_000, "Timeout ms")
+	target.bind(cmd, "ref")
+	cmd.Flags().StringVar(&strategy, "strategy", "auto", "Fill strategy (auto|fill|set_value|type|select_option|set_checked)")
+	effects.bind(cmd)
+	after.bind(cmd)
 
 	return cmd
 }
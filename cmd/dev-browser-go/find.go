@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newFindCmd() *cobra.Command {
+	var pageName string
+	var target targetFlags
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Resolve any element target to a ref",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			payload := map[string]interface{}{
+				"timeout_ms": timeout,
+			}
+			ok, err := target.apply(payload)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return usageErrorf("a target flag is required")
+			}
+			return runWithPage(pageName, "find", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	target.bind(cmd, "ref")
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "Timeout ms")
+
+	return cmd
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newFormsCmd() *cobra.Command {
+	var pageName string
+	var formSel string
+
+	cmd := &cobra.Command{
+		Use:   "forms",
+		Short: "List forms and their fields",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			payload := map[string]interface{}{}
+			if strings.TrimSpace(formSel) != "" {
+				payload["form"] = formSel
+			}
+			return runWithPage(pageName, "forms", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringVar(&formSel, "form", "", "Form index (1-based), id or name")
+
+	return cmd
+}
+
+func newFillFormCmd() *cobra.Command {
+	var pageName string
+	var valuesArg string
+	var formSel string
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "fill-form",
+		Short: "Fill form fields by label or name from JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			raw := strings.TrimSpace(valuesArg)
+			if raw == "" {
+				b, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return err
+				}
+				raw = string(b)
+			}
+			values, err := orderedFormValues([]byte(raw))
+			if err != nil {
+				return usageErrorf("invalid JSON for --values/stdin")
+			}
+			payload := map[string]interface{}{
+				"values":     values,
+				"timeout_ms": timeout,
+			}
+			if strings.TrimSpace(formSel) != "" {
+				payload["form"] = formSel
+			}
+			return runWithPage(pageName, "fill_form", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringVar(&valuesArg, "values", "", "JSON object of label/name to value, filled in the order given (or stdin)")
+	cmd.Flags().StringVar(&formSel, "form", "", "Form index (1-based), id or name")
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 15_000, "Timeout ms per field")
+
+	return cmd
+}
+
+// orderedFormValues turns a JSON object into fill_form's list form so the
+// fields are filled in the order the keys were written. A list is passed
+// through as is.
+func orderedFormValues(raw []byte) ([]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		list := []interface{}{}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	out := []interface{}{}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		out = append(out, map[string]interface{}{"field": key, "value": value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"errors"
 	"os"
 	"strconv"
 	"strings"
@@ -41,7 +40,7 @@ func applyGlobalOptions(cmd *cobra.Command) error {
 		return err
 	}
 	if globalOpts.output != "summary" && globalOpts.output != "json" && globalOpts.output != "path" {
-		return errors.New("--output must be summary|json|path")
+		return usageErrorf("--output must be summary|json|path")
 	}
 	return nil
 }
@@ -50,14 +49,14 @@ func resolveHeadless(cmd *cobra.Command) error {
 	headlessChanged := cmd.Flags().Changed("headless")
 	headedChanged := cmd.Flags().Changed("headed")
 	if headedChanged && headlessChanged {
-		return errors.New("use either --headless or --headed")
+		return usageErrorf("use either --headless or --headed")
 	}
 	if headedChanged {
 		globalOpts.headless = false
 		return nil
 	}
 	if headlessChanged && !globalOpts.headless {
-		return errors.New("use --headed to disable headless")
+		return usageErrorf("use --headed to disable headless")
 	}
 	return nil
 }
@@ -65,7 +64,7 @@ func resolveHeadless(cmd *cobra.Command) error {
 func resolveWindow(cmd *cobra.Command) error {
 	windowScaleChanged := cmd.Flags().Changed("window-scale")
 	if strings.TrimSpace(globalOpts.windowSize) != "" && windowScaleChanged {
-		return errors.New("use either --window-size or --window-scale")
+		return usageErrorf("use either --window-size or --window-scale")
 	}
 	scaleVal := 1.0
 	if windowScaleChanged {
@@ -114,7 +113,7 @@ func getenvInt(name string, def int) int {
 func requireArgs(count int, errMsg string) cobra.PositionalArgs {
 	return func(_ *cobra.Command, args []string) error {
 		if len(args) != count {
-			return errors.New(errMsg)
+			return usageErrorf("%s", errMsg)
 		}
 		return nil
 	}
@@ -123,7 +122,7 @@ func requireArgs(count int, errMsg string) cobra.PositionalArgs {
 func maxArgs(max int, errMsg string) cobra.PositionalArgs {
 	return func(_ *cobra.Command, args []string) error {
 		if len(args) > max {
-			return errors.New(errMsg)
+			return usageErrorf("%s", errMsg)
 		}
 		return nil
 	}
@@ -8,6 +8,7 @@ func newGotoCmd() *cobra.Command {
 	var pageName string
 	var waitUntil string
 	var timeout int
+	var expectStatus string
 
 	cmd := &cobra.Command{
 		Use:   "goto <url>",
@@ -19,6 +20,9 @@ func newGotoCmd() *cobra.Command {
 				"wait_until": waitUntil,
 				"timeout_ms": timeout,
 			}
+			if expectStatus != "" {
+				payload["expect_status"] = expectStatus
+			}
 			return runWithPage(pageName, "goto", payload)
 		},
 	}
@@ -26,6 +30,7 @@ func newGotoCmd() *cobra.Command {
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
 	cmd.Flags().StringVar(&waitUntil, "wait-until", "domcontentloaded", "Wait strategy")
 	cmd.Flags().IntVar(&timeout, "timeout-ms", 45_000, "Timeout ms")
+	cmd.Flags().StringVar(&expectStatus, "expect-status", "", "Fail unless the status matches (e.g. 200, 2xx, 200-299, 200,304)")
 
 	return cmd
 }
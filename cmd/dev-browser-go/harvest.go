@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newHarvestCmd() *cobra.Command {
+	var pageName string
+	var target targetFlags
+	var rowSelector string
+	var interactiveOnly bool
+	var maxItems int
+	var timeout int
+	var maxDuration int
+	var idleRounds int
+	var settle int
+	var scrollPx float64
+	var inlineLimit int
+	var pathArg string
+
+	cmd := &cobra.Command{
+		Use:   "harvest",
+		Short: "Scroll and collect items from virtualized lists/feeds",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return applyNoFlag(cmd, "interactive-only")
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			payload := map[string]interface{}{
+				"interactive_only": interactiveOnly,
+				"max_items":        maxItems,
+				"timeout_ms":       timeout,
+				"max_duration_ms":  maxDuration,
+				"idle_rounds":      idleRounds,
+				"settle_ms":        settle,
+				"inline_limit":     inlineLimit,
+			}
+			if _, err := target.apply(payload); err != nil {
+				return err
+			}
+			if strings.TrimSpace(rowSelector) != "" {
+				payload["row_selector"] = rowSelector
+			}
+			if cmd.Flags().Changed("scroll-px") {
+				payload["scroll_px"] = scrollPx
+			}
+			if strings.TrimSpace(pathArg) != "" {
+				payload["path"] = pathArg
+			}
+			return runWithPage(pageName, "harvest", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	target.bind(cmd)
+	cmd.Flags().StringVar(&rowSelector, "row-selector", "", "Collect innerText of rows matching selector (default: snapshot items)")
+	cmd.Flags().BoolVar(&interactiveOnly, "interactive-only", true, "Only interactive snapshot items")
+	cmd.Flags().IntVar(&maxItems, "max-items", 500, "Stop after this many unique items")
+	cmd.Flags().IntVar(&maxDuration, "max-duration-ms", 30_000, "Stop after this long")
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "Timeout ms for finding the container")
+	cmd.Flags().IntVar(&idleRounds, "idle-rounds", 3, "Stop after this many scrolls without new items")
+	cmd.Flags().IntVar(&settle, "settle-ms", 300, "Wait after each scroll")
+	cmd.Flags().Float64Var(&scrollPx, "scroll-px", 0, "Pixels per scroll (default: 90% of container height)")
+	cmd.Flags().IntVar(&inlineLimit, "inline-limit", 100, "Write to artifact file above this many items")
+	cmd.Flags().StringVar(&pathArg, "path", "", "Output path (always write file)")
+	cmd.Flags().Bool("no-interactive-only", false, "Include non-interactive elements")
+
+	return cmd
+}
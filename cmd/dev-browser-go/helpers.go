@@ -21,7 +21,7 @@ func runWithPage(pageName, tool string, args map[string]interface{}) error {
 	defer browser.Close()
 	defer pw.Stop()
 
-	res, err := devbrowser.RunCall(page, tool, args, devbrowser.ArtifactDir(globalOpts.profile))
+	res, err := devbrowser.RunCall(page, tool, args, callOptions(pageName))
 	if err != nil {
 		return err
 	}
@@ -33,13 +33,23 @@ func runWithPage(pageName, tool string, args map[string]interface{}) error {
 	return nil
 }
 
+// callOptions gives tool calls on pageName the profile's artifact dir, the
+// daemon's page events and the page's export log.
+func callOptions(pageName string) devbrowser.CallOptions {
+	return devbrowser.CallOptions{
+		ArtifactDir: devbrowser.ArtifactDir(globalOpts.profile),
+		Events:      devbrowser.DaemonPageEvents(globalOpts.profile, pageName),
+		SessionLog:  devbrowser.SessionLogPath(globalOpts.profile, pageName),
+	}
+}
+
 func startDaemonIfNeeded() (string, error) {
 	if err := devbrowser.StartDaemon(globalOpts.profile, globalOpts.headless, globalOpts.window); err != nil {
-		return "", err
+		return "", devbrowser.NewToolError(devbrowser.ErrDaemonUnavailable, err, nil)
 	}
 	base := devbrowser.DaemonBaseURL(globalOpts.profile)
 	if base == "" {
-		return "", errors.New("daemon state missing after start")
+		return "", devbrowser.NewToolError(devbrowser.ErrDaemonUnavailable, errors.New("daemon state missing after start"), nil)
 	}
 	return base, nil
 }
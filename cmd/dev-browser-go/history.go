@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newHistoryCmd(use, tool, short string) *cobra.Command {
+	var pageName string
+	var waitUntil string
+	var timeout int
+	var bypassCache bool
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			payload := map[string]interface{}{
+				"wait_until": waitUntil,
+				"timeout_ms": timeout,
+			}
+			if tool == "reload" {
+				payload["bypass_cache"] = bypassCache
+			}
+			return runWithPage(pageName, tool, payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringVar(&waitUntil, "wait-until", "domcontentloaded", "Wait strategy")
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 45_000, "Timeout ms")
+	if tool == "reload" {
+		cmd.Flags().BoolVar(&bypassCache, "bypass-cache", false, "Reload ignoring the HTTP cache")
+	}
+
+	return cmd
+}
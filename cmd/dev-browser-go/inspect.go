@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newInspectRefCmd() *cobra.Command {
+	var pageName string
+	var target targetFlags
+	var maxHTML int
+	var listeners bool
+	var timeout int
+	var maxTokens int
+
+	cmd := &cobra.Command{
+		Use:   "inspect-ref [ref]",
+		Short: "Report everything about one element: attributes, a11y name, styles, box, occlusion, listeners, HTML",
+		Args:  maxArgs(1, "inspect-ref takes one ref"),
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if maxHTML < 0 {
+				return usageErrorf("--max-html-chars must be >= 0")
+			}
+			if maxTokens < 0 {
+				return usageErrorf("--max-tokens must be >= 0")
+			}
+			return applyNoFlag(cmd, "listeners")
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				target.ref = args[0]
+			}
+			payload := map[string]interface{}{
+				"max_html_chars": maxHTML,
+				"listeners":      listeners,
+				"timeout_ms":     timeout,
+			}
+			if maxTokens > 0 {
+				payload["max_tokens"] = maxTokens
+			}
+			ok, err := target.apply(payload)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return usageErrorf("ref or a target flag required")
+			}
+			return runWithPage(pageName, "inspect_ref", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	target.bind(cmd, "ref")
+	cmd.Flags().IntVar(&maxHTML, "max-html-chars", 2_000, "Truncate outerHTML to this many chars (0 = omit)")
+	cmd.Flags().BoolVar(&listeners, "listeners", true, "Include event listeners on the element and its ancestors")
+	cmd.Flags().Bool("no-listeners", false, "Skip event listeners")
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "Timeout ms")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Approximate token budget for the HTML (0 = off)")
+
+	return cmd
+}
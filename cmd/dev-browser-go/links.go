@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newLinksCmd() *cobra.Command {
+	var pageName string
+	var include []string
+	var exclude []string
+	var scope string
+	var limit int
+	var maxTokens int
+
+	cmd := &cobra.Command{
+		Use:   "links",
+		Short: "List page links",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if maxTokens < 0 {
+				return usageErrorf("--max-tokens must be >= 0")
+			}
+			payload := map[string]interface{}{
+				"scope": scope,
+				"limit": limit,
+			}
+			if len(include) > 0 {
+				payload["include"] = include
+			}
+			if len(exclude) > 0 {
+				payload["exclude"] = exclude
+			}
+			if maxTokens > 0 {
+				payload["max_tokens"] = maxTokens
+			}
+			return runWithPage(pageName, "links", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringArrayVar(&include, "include", nil, "Only links matching glob (repeatable, e.g. /docs/*)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Skip links matching glob (repeatable)")
+	cmd.Flags().StringVar(&scope, "scope", "all", "Link scope (all|internal|external)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Max links (0 = no limit)")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Approximate token budget (0 = off)")
+
+	return cmd
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,7 +19,7 @@ func newListPagesCmd() *cobra.Command {
 			}
 			base := devbrowser.DaemonBaseURL(globalOpts.profile)
 			if base == "" {
-				return fmt.Errorf("daemon state missing after start")
+				return devbrowser.NewToolError(devbrowser.ErrDaemonUnavailable, errors.New("daemon state missing after start"), nil)
 			}
 			data, err := devbrowser.HTTPJSON("GET", base+"/pages", nil, 3*time.Second)
 			if err != nil {
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 )
 
@@ -9,12 +8,7 @@ func main() {
 	if len(os.Args) > 1 && os.Args[1] == "--daemon" {
 		os.Args = append([]string{os.Args[0], "daemon"}, os.Args[2:]...)
 	}
-	if err := rejectBoolEqualsArgs(os.Args[1:]); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
 	if err := Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(reportError(err))
 	}
 }
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type mouseTargetFlags struct {
+	pageName string
+	target   targetFlags
+	timeout  int
+	button   string
+	x, y     float64
+	cmd      *cobra.Command
+}
+
+func (f *mouseTargetFlags) bind(cmd *cobra.Command, withButton bool) {
+	f.cmd = cmd
+	cmd.Flags().StringVar(&f.pageName, "page", "main", "Page name")
+	f.target.bind(cmd)
+	// Negative positional coordinates read as flags, so they also have
+	// flags of their own (or go after --).
+	cmd.Flags().Float64Var(&f.x, "x", 0, "X coordinate (instead of the positional x y)")
+	cmd.Flags().Float64Var(&f.y, "y", 0, "Y coordinate (instead of the positional x y)")
+	cmd.Flags().IntVar(&f.timeout, "timeout-ms", 5_000, "Timeout ms for target wait")
+	if withButton {
+		cmd.Flags().StringVar(&f.button, "button", "left", "Mouse button (left|right|middle)")
+	}
+}
+
+func (f *mouseTargetFlags) payload(args []string) (map[string]interface{}, error) {
+	payload := map[string]interface{}{
+		"timeout_ms": f.timeout,
+	}
+	if strings.TrimSpace(f.button) != "" {
+		payload["button"] = f.button
+	}
+	if _, err := f.target.apply(payload); err != nil {
+		return nil, err
+	}
+	hasX, hasY := f.cmd.Flags().Changed("x"), f.cmd.Flags().Changed("y")
+	switch {
+	case len(args) == 2 && (hasX || hasY):
+		return nil, usageErrorf("give coordinates as x y or as --x/--y, not both")
+	case len(args) == 2:
+		x, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return nil, usageErrorf("invalid x: %s", args[0])
+		}
+		y, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return nil, usageErrorf("invalid y: %s", args[1])
+		}
+		payload["x"] = x
+		payload["y"] = y
+	case hasX != hasY:
+		return nil, usageErrorf("--x and --y must be given together")
+	case hasX:
+		payload["x"] = f.x
+		payload["y"] = f.y
+	}
+	return payload, nil
+}
+
+func xyArgs(_ *cobra.Command, args []string) error {
+	if len(args) != 0 && len(args) != 2 {
+		return usageErrorf("expected x y (or none with a target or --x/--y)")
+	}
+	return nil
+}
+
+func newMouseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mouse",
+		Short: "Mouse actions by CSS pixel coordinates",
+	}
+	cmd.AddCommand(
+		newMouseClickCmd(),
+		newMouseMoveCmd(),
+		newMouseButtonCmd("down", "mouse_down", "Press mouse button"),
+		newMouseButtonCmd("up", "mouse_up", "Release mouse button"),
+		newMouseWheelCmd(),
+	)
+	return cmd
+}
+
+func newMouseClickCmd() *cobra.Command {
+	flags := &mouseTargetFlags{}
+	var clickCount int
+	var delay int
+
+	cmd := &cobra.Command{
+		Use:   "click [x y]",
+		Short: "Click at coordinates",
+		Args:  xyArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			payload, err := flags.payload(args)
+			if err != nil {
+				return err
+			}
+			payload["click_count"] = clickCount
+			payload["delay_ms"] = delay
+			return runWithPage(flags.pageName, "click_xy", payload)
+		},
+	}
+	flags.bind(cmd, true)
+	cmd.Flags().IntVar(&clickCount, "click-count", 1, "Click count (2 = double click)")
+	cmd.Flags().IntVar(&delay, "delay-ms", 0, "Delay between down and up")
+	return cmd
+}
+
+func newMouseMoveCmd() *cobra.Command {
+	flags := &mouseTargetFlags{}
+	var steps int
+
+	cmd := &cobra.Command{
+		Use:   "move [x y]",
+		Short: "Move pointer to coordinates",
+		Args:  xyArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			payload, err := flags.payload(args)
+			if err != nil {
+				return err
+			}
+			payload["steps"] = steps
+			return runWithPage(flags.pageName, "mouse_move", payload)
+		},
+	}
+	flags.bind(cmd, false)
+	cmd.Flags().IntVar(&steps, "steps", 1, "Intermediate move events")
+	return cmd
+}
+
+func newMouseButtonCmd(use, tool, short string) *cobra.Command {
+	flags := &mouseTargetFlags{}
+
+	cmd := &cobra.Command{
+		Use:   use + " [x y]",
+		Short: short,
+		Args:  xyArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			payload, err := flags.payload(args)
+			if err != nil {
+				return err
+			}
+			return runWithPage(flags.pageName, tool, payload)
+		},
+	}
+	flags.bind(cmd, true)
+	return cmd
+}
+
+func newMouseWheelCmd() *cobra.Command {
+	flags := &mouseTargetFlags{}
+	var deltaX float64
+	var deltaY float64
+
+	cmd := &cobra.Command{
+		Use:   "wheel [x y]",
+		Short: "Scroll wheel at coordinates",
+		Args:  xyArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			payload, err := flags.payload(args)
+			if err != nil {
+				return err
+			}
+			payload["delta_x"] = deltaX
+			payload["delta_y"] = deltaY
+			return runWithPage(flags.pageName, "mouse_wheel", payload)
+		},
+	}
+	flags.bind(cmd, false)
+	cmd.Flags().Float64Var(&deltaX, "delta-x", 0, "Horizontal wheel delta")
+	cmd.Flags().Float64Var(&deltaY, "delta-y", 0, "Vertical wheel delta (positive scrolls down)")
+	return cmd
+}
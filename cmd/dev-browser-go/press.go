@@ -6,18 +6,26 @@ import (
 
 func newPressCmd() *cobra.Command {
 	var pageName string
+	var effects effectsFlags
+	var after afterFlags
 
 	cmd := &cobra.Command{
 		Use:   "press <key>",
 		Short: "Send key press",
 		Args:  requireArgs(1, "key required"),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			payload := map[string]interface{}{"key": args[0]}
+			effects.apply(payload)
+			if err := after.apply(cmd, payload); err != nil {
+				return err
+			}
 			return runWithPage(pageName, "press", payload)
 		},
 	}
 
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	effects.bind(cmd)
+	after.bind(cmd)
 
 	return cmd
 }
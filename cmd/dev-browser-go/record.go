@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	devbrowser "github.com/joshp123/dev-browser-go/internal/devbrowser"
+	"github.com/spf13/cobra"
+)
+
+func newRecordCmd() *cobra.Command {
+	var pageName string
+	var format string
+	var filePath string
+	var durationMs int
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "record",
+		Short: "Record clicks, typing and navigation into an actions or scenario file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "actions" && format != "scenario" {
+				return usageErrorf("--format must be actions or scenario")
+			}
+			if durationMs < 0 {
+				return usageErrorf("--duration-ms must be >= 0")
+			}
+
+			ws, tid, err := devbrowser.EnsurePage(globalOpts.profile, globalOpts.headless, pageName, globalOpts.window)
+			if err != nil {
+				return err
+			}
+			pw, browser, page, err := devbrowser.OpenPage(ws, tid)
+			if err != nil {
+				return err
+			}
+			defer browser.Close()
+			defer pw.Stop()
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			if durationMs > 0 {
+				var cancelTimeout context.CancelFunc
+				ctx, cancelTimeout = context.WithTimeout(ctx, time.Duration(durationMs)*time.Millisecond)
+				defer cancelTimeout()
+				fmt.Fprintf(os.Stderr, "recording page '%s' for %dms...\n", pageName, durationMs)
+			} else {
+				fmt.Fprintf(os.Stderr, "recording page '%s', press Ctrl-C to stop...\n", pageName)
+			}
+
+			events, err := devbrowser.Record(page, ctx.Done())
+			if err != nil {
+				return err
+			}
+			steps, vars := devbrowser.RecordedSteps(events)
+
+			var data []byte
+			ext := "json"
+			if format == "scenario" {
+				ext = "yaml"
+				if name == "" {
+					name = "recorded"
+				}
+				if data, err = devbrowser.RecordedScenarioYAML(name, steps, vars); err != nil {
+					return err
+				}
+			} else {
+				var doc any = steps
+				if len(vars) > 0 {
+					doc = map[string]any{"vars": vars, "steps": steps}
+				}
+				if data, err = json.MarshalIndent(doc, "", "  "); err != nil {
+					return err
+				}
+				data = append(data, '\n')
+			}
+
+			path, err := devbrowser.SafeArtifactPath(devbrowser.ArtifactDir(globalOpts.profile), filePath, fmt.Sprintf("record-%d.%s", devbrowser.NowMS(), ext))
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return err
+			}
+
+			result := map[string]any{"path": path, "format": format, "events": len(events), "steps": len(steps)}
+			if len(vars) > 0 {
+				result["vars"] = vars
+			}
+			out, err := devbrowser.WriteOutput(globalOpts.profile, globalOpts.output, result, globalOpts.outPath)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringVar(&format, "format", "actions", "Output format: actions (JSON for the actions command) or scenario (YAML for run)")
+	cmd.Flags().StringVar(&filePath, "file", "", "Write the recording to this path (default: artifact dir)")
+	cmd.Flags().IntVar(&durationMs, "duration-ms", 0, "Stop after this long (0 = until Ctrl-C)")
+	cmd.Flags().StringVar(&name, "name", "", "Scenario name (scenario format)")
+
+	return cmd
+}
@@ -1,17 +1,25 @@
 package main
 
 import (
+	"os"
+
+	devbrowser "github.com/joshp123/dev-browser-go/internal/devbrowser"
 	"github.com/spf13/cobra"
 )
 
 const version = "0.2.0"
 
 var rootCmd = &cobra.Command{
-	Use:          "dev-browser-go",
-	Short:        "ref-based browser automation (CLI + daemon)",
-	SilenceUsage: true,
+	Use:           "dev-browser-go",
+	Short:         "ref-based browser automation (CLI + daemon)",
+	SilenceUsage:  true,
+	SilenceErrors: true,
 	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
-		return applyGlobalOptions(cmd)
+		if err := applyGlobalOptions(cmd); err != nil {
+			return err
+		}
+		// Checked after flag parsing so the error honours --output json.
+		return rejectBoolEqualsArgs(os.Args[1:])
 	},
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		return cmd.Help()
@@ -27,6 +35,9 @@ func init() {
 	rootCmd.SetVersionTemplate("{{.Version}}\n")
 
 	bindGlobalFlags(rootCmd)
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return devbrowser.NewToolError(devbrowser.ErrInvalidArgument, err, nil)
+	})
 
 	rootCmd.AddCommand(
 		newDaemonCmd(),
@@ -35,17 +46,34 @@ func init() {
 		newStopCmd(),
 		newListPagesCmd(),
 		newGotoCmd(),
+		newHistoryCmd("back", "back", "Go back in history"),
+		newHistoryCmd("forward", "forward", "Go forward in history"),
+		newHistoryCmd("reload", "reload", "Reload page"),
 		newSnapshotCmd(),
 		newClickRefCmd(),
 		newFillRefCmd(),
+		newFormsCmd(),
+		newFillFormCmd(),
 		newPressCmd(),
+		newMouseCmd(),
+		newScrollCmd(),
+		newScrollIntoViewCmd(),
+		newHarvestCmd(),
 		newScreenshotCmd(),
 		newBoundsCmd(),
+		newInspectRefCmd(),
+		newLinksCmd(),
+		newTableCmd(),
 		newConsoleCmd(),
 		newSaveHTMLCmd(),
 		newWaitCmd(),
+		newExpectCmd(),
+		newFindCmd(),
 		newCallCmd(),
 		newActionsCmd(),
+		newRunCmd(),
+		newRecordCmd(),
+		newExportCmd(),
 		newClosePageCmd(),
 	)
 }
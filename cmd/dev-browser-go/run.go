@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	devbrowser "github.com/joshp123/dev-browser-go/internal/devbrowser"
+	"github.com/spf13/cobra"
+)
+
+func newRunCmd() *cobra.Command {
+	var pageName string
+	var junitPath string
+	var jsonPath string
+	var only []string
+	var varsArg []string
+
+	cmd := &cobra.Command{
+		Use:   "run <scenario.yaml>",
+		Short: "Run a YAML scenario file and write JUnit/JSON reports",
+		Args:  requireArgs(1, "scenario file required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return usageErrorf("read scenario file: %v", err)
+			}
+			base := strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+			file, err := devbrowser.ParseScenarioFile(data, base)
+			if err != nil {
+				return err
+			}
+			known := map[string]bool{}
+			for _, sc := range file.Scenarios {
+				known[sc.Name] = true
+			}
+			for _, name := range only {
+				if !known[name] {
+					return usageErrorf("unknown scenario '%s'", name)
+				}
+			}
+			vars := map[string]interface{}{}
+			for _, kv := range varsArg {
+				name, value, ok := strings.Cut(kv, "=")
+				if !ok || strings.TrimSpace(name) == "" {
+					return usageErrorf("--var must be name=value")
+				}
+				vars[strings.TrimSpace(name)] = value
+			}
+			if !cmd.Flags().Changed("page") && file.Page != "" {
+				pageName = file.Page
+			}
+
+			ws, tid, err := devbrowser.EnsurePage(globalOpts.profile, globalOpts.headless, pageName, globalOpts.window)
+			if err != nil {
+				return err
+			}
+			pw, browser, page, err := devbrowser.OpenPage(ws, tid)
+			if err != nil {
+				return err
+			}
+			defer browser.Close()
+			defer pw.Stop()
+
+			// Scenario steps are not added to the page's export log.
+			call := callOptions(pageName)
+			call.SessionLog = ""
+			artifactDir := call.ArtifactDir
+			report := devbrowser.RunScenarios(page, file, devbrowser.ScenarioOptions{
+				CallOptions: call,
+				Vars:        vars,
+				Only:        only,
+			})
+
+			var result map[string]any
+			enc, err := json.Marshal(report)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(enc, &result); err != nil {
+				return err
+			}
+
+			// Relative report paths land in the artifact dir like --out;
+			// the result says where.
+			if junitPath != "" {
+				data, err := report.JUnitXML()
+				if err != nil {
+					return err
+				}
+				path, err := writeReport(artifactDir, junitPath, data)
+				if err != nil {
+					return err
+				}
+				result["junit_path"] = path
+			}
+			if jsonPath != "" {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				path, err := writeReport(artifactDir, jsonPath, append(data, '\n'))
+				if err != nil {
+					return err
+				}
+				result["json_path"] = path
+			}
+			out, err := devbrowser.WriteOutput(globalOpts.profile, globalOpts.output, result, globalOpts.outPath)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+			return report.Err()
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name (overrides the file's page)")
+	cmd.Flags().StringVar(&junitPath, "junit", "", "Write a JUnit XML report to this path")
+	cmd.Flags().StringVar(&jsonPath, "json", "", "Write a JSON report to this path")
+	cmd.Flags().StringArrayVar(&only, "scenario", nil, "Only run this scenario (repeatable)")
+	cmd.Flags().StringArrayVar(&varsArg, "var", nil, "Override a file var as name=value (repeatable)")
+
+	return cmd
+}
+
+// writeReport writes a report under the same path rules as --out and
+// returns the absolute path it wrote.
+func writeReport(artifactDir, pathArg string, data []byte) (string, error) {
+	path, err := devbrowser.SafeArtifactPath(artifactDir, pathArg, filepath.Base(pathArg))
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0o644)
+}
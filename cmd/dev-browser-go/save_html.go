@@ -7,19 +7,27 @@ import (
 func newSaveHTMLCmd() *cobra.Command {
 	var pageName string
 	var pathArg string
+	var maxTokens int
 
 	cmd := &cobra.Command{
 		Use:   "save-html",
 		Short: "Save page HTML",
 		Args:  cobra.NoArgs,
 		RunE: func(_ *cobra.Command, _ []string) error {
+			if maxTokens < 0 {
+				return usageErrorf("--max-tokens must be >= 0")
+			}
 			payload := map[string]interface{}{"path": pathArg}
+			if maxTokens > 0 {
+				payload["max_tokens"] = maxTokens
+			}
 			return runWithPage(pageName, "save_html", payload)
 		},
 	}
 
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
 	cmd.Flags().StringVar(&pathArg, "path", "", "Output path")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Approximate token budget for an inline copy of the HTML (0 = off)")
 
 	return cmd
 }
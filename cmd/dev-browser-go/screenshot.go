@@ -12,10 +12,7 @@ func newScreenshotCmd() *cobra.Command {
 	var fullPage bool
 	var annotate bool
 	var crop string
-	var selector string
-	var ariaRole string
-	var ariaName string
-	var nth int
+	var target targetFlags
 	var padding int
 	var timeout int
 
@@ -30,7 +27,6 @@ func newScreenshotCmd() *cobra.Command {
 			payload := map[string]interface{}{
 				"full_page":     fullPage,
 				"annotate_refs": annotate,
-				"nth":           nth,
 				"padding_px":    padding,
 				"timeout_ms":    timeout,
 			}
@@ -40,14 +36,8 @@ func newScreenshotCmd() *cobra.Command {
 			if strings.TrimSpace(crop) != "" {
 				payload["crop"] = crop
 			}
-			if strings.TrimSpace(selector) != "" {
-				payload["selector"] = selector
-			}
-			if strings.TrimSpace(ariaRole) != "" {
-				payload["aria_role"] = ariaRole
-			}
-			if strings.TrimSpace(ariaName) != "" {
-				payload["aria_name"] = ariaName
+			if _, err := target.apply(payload); err != nil {
+				return err
 			}
 			return runWithPage(pageName, "screenshot", payload)
 		},
@@ -58,10 +48,7 @@ func newScreenshotCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&fullPage, "full-page", true, "Full page")
 	cmd.Flags().BoolVar(&annotate, "annotate-refs", false, "Annotate refs")
 	cmd.Flags().StringVar(&crop, "crop", "", "Crop x,y,w,h")
-	cmd.Flags().StringVar(&selector, "selector", "", "CSS selector for element crop")
-	cmd.Flags().StringVar(&ariaRole, "aria-role", "", "ARIA role for element crop")
-	cmd.Flags().StringVar(&ariaName, "aria-name", "", "ARIA name for element crop")
-	cmd.Flags().IntVar(&nth, "nth", 1, "Nth match (1-based)")
+	target.bind(cmd)
 	cmd.Flags().IntVar(&padding, "padding-px", 10, "Padding around element in px")
 	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "Timeout ms for element wait")
 	cmd.Flags().Bool("no-full-page", false, "Disable full page")
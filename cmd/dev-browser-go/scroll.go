@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newScrollCmd() *cobra.Command {
+	var pageName string
+	var dx float64
+	var dy float64
+	var to string
+	var target targetFlags
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "scroll",
+		Short: "Scroll page or container",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			payload := map[string]interface{}{
+				"timeout_ms": timeout,
+			}
+			if _, err := target.apply(payload); err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("dx") {
+				payload["dx"] = dx
+			}
+			if cmd.Flags().Changed("dy") {
+				payload["dy"] = dy
+			}
+			if strings.TrimSpace(to) != "" {
+				payload["to"] = to
+			}
+			return runWithPage(pageName, "scroll", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().Float64Var(&dx, "dx", 0, "Horizontal pixels")
+	cmd.Flags().Float64Var(&dy, "dy", 0, "Vertical pixels (default: one screen down)")
+	cmd.Flags().StringVar(&to, "to", "", "Scroll to edge (top|bottom|left|right)")
+	target.bind(cmd)
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "Timeout ms for container wait")
+
+	return cmd
+}
+
+func newScrollIntoViewCmd() *cobra.Command {
+	var pageName string
+	var block string
+	var target targetFlags
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "scroll-into-view [ref]",
+		Short: "Scroll element into view by ref (or any target flag)",
+		Args:  maxArgs(1, "scroll-into-view takes one ref"),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				target.ref = args[0]
+			}
+			payload := map[string]interface{}{
+				"timeout_ms": timeout,
+			}
+			ok, err := target.apply(payload)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return usageErrorf("ref or a target flag required")
+			}
+			if strings.TrimSpace(block) != "" {
+				payload["block"] = block
+			}
+			return runWithPage(pageName, "scroll_into_view_ref", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	cmd.Flags().StringVar(&block, "block", "", "Alignment (start|center|end|nearest; default only if needed)")
+	target.bind(cmd, "ref")
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "Timeout ms")
+
+	return cmd
+}
@@ -1,62 +1,91 @@
 package main
 
 import (
-	"errors"
-
 	"github.com/spf13/cobra"
 )
 
+type snapshotFlags struct {
+	engine          string
+	format          string
+	interactiveOnly bool
+	includeHeadings bool
+	maxItems        int
+	maxChars        int
+	maxTokens       int
+	withLocators    bool
+}
+
+func (f *snapshotFlags) bind(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.engine, "engine", "simple", "Engine (simple|aria)")
+	cmd.Flags().StringVar(&f.format, "format", "list", "Format (list|json|yaml)")
+	cmd.Flags().BoolVar(&f.interactiveOnly, "interactive-only", true, "Only interactive elements")
+	cmd.Flags().BoolVar(&f.includeHeadings, "include-headings", true, "Include headings")
+	cmd.Flags().IntVar(&f.maxItems, "max-items", 80, "Max items")
+	cmd.Flags().IntVar(&f.maxChars, "max-chars", 8000, "Max chars")
+	cmd.Flags().IntVar(&f.maxTokens, "max-tokens", 0, "Approximate token budget (0 = off)")
+	cmd.Flags().BoolVar(&f.withLocators, "with-locators", false, "Add a unique Playwright locator to each item")
+
+	cmd.Flags().Bool("no-interactive-only", false, "Include non-interactive elements")
+	cmd.Flags().Bool("no-include-headings", false, "Exclude headings")
+}
+
+func (f *snapshotFlags) validate(cmd *cobra.Command) error {
+	if err := applyNoFlag(cmd, "interactive-only"); err != nil {
+		return err
+	}
+	if err := applyNoFlag(cmd, "include-headings"); err != nil {
+		return err
+	}
+	if f.maxItems < 0 {
+		return usageErrorf("--max-items must be >= 0")
+	}
+	if f.maxChars < 0 {
+		return usageErrorf("--max-chars must be >= 0")
+	}
+	if f.maxTokens < 0 {
+		return usageErrorf("--max-tokens must be >= 0")
+	}
+	return nil
+}
+
+func (f *snapshotFlags) payload(cmd *cobra.Command) map[string]interface{} {
+	payload := map[string]interface{}{
+		"engine":           f.engine,
+		"format":           f.format,
+		"interactive_only": f.interactiveOnly,
+		"include_headings": f.includeHeadings,
+		"max_items":        f.maxItems,
+	}
+	if cmd.Flags().Changed("max-chars") || f.maxTokens == 0 {
+		payload["max_chars"] = f.maxChars
+	}
+	if f.maxTokens > 0 {
+		payload["max_tokens"] = f.maxTokens
+	}
+	if f.withLocators {
+		payload["with_locators"] = true
+	}
+	return payload
+}
+
 func newSnapshotCmd() *cobra.Command {
 	var pageName string
-	var engine string
-	var format string
-	var interactiveOnly bool
-	var includeHeadings bool
-	var maxItems int
-	var maxChars int
+	var snap snapshotFlags
 
 	cmd := &cobra.Command{
 		Use:   "snapshot",
 		Short: "Get accessibility snapshot",
 		Args:  cobra.NoArgs,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			payload := map[string]interface{}{
-				"engine":           engine,
-				"format":           format,
-				"interactive_only": interactiveOnly,
-				"include_headings": includeHeadings,
-				"max_items":        maxItems,
-				"max_chars":        maxChars,
-			}
-			return runWithPage(pageName, "snapshot", payload)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runWithPage(pageName, "snapshot", snap.payload(cmd))
 		},
 	}
 
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
-	cmd.Flags().StringVar(&engine, "engine", "simple", "Engine (simple|aria)")
-	cmd.Flags().StringVar(&format, "format", "list", "Format (list|json|yaml)")
-	cmd.Flags().BoolVar(&interactiveOnly, "interactive-only", true, "Only interactive elements")
-	cmd.Flags().BoolVar(&includeHeadings, "include-headings", true, "Include headings")
-	cmd.Flags().IntVar(&maxItems, "max-items", 80, "Max items")
-	cmd.Flags().IntVar(&maxChars, "max-chars", 8000, "Max chars")
-
-	cmd.Flags().Bool("no-interactive-only", false, "Include non-interactive elements")
-	cmd.Flags().Bool("no-include-headings", false, "Exclude headings")
+	snap.bind(cmd)
 
 	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
-		if err := applyNoFlag(cmd, "interactive-only"); err != nil {
-			return err
-		}
-		if err := applyNoFlag(cmd, "include-headings"); err != nil {
-			return err
-		}
-		if maxItems < 0 {
-			return errors.New("--max-items must be >= 0")
-		}
-		if maxChars < 0 {
-			return errors.New("--max-chars must be >= 0")
-		}
-		return nil
+		return snap.validate(cmd)
 	}
 
 	return cmd
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newTableCmd() *cobra.Command {
+	var pageName string
+	var target targetFlags
+	var timeout int
+	var expandSpans bool
+	var maxRows int
+	var format string
+	var pathArg string
+	var maxTokens int
+
+	cmd := &cobra.Command{
+		Use:   "table [selector]",
+		Short: "Extract table as JSON or CSV",
+		Args:  maxArgs(1, "too many arguments"),
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return applyNoFlag(cmd, "expand-spans")
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 0 && strings.TrimSpace(target.selector) == "" {
+				target.selector = args[0]
+			}
+			if maxTokens < 0 {
+				return usageErrorf("--max-tokens must be >= 0")
+			}
+			payload := map[string]interface{}{
+				"timeout_ms":   timeout,
+				"expand_spans": expandSpans,
+				"max_rows":     maxRows,
+				"format":       format,
+			}
+			if _, err := target.apply(payload); err != nil {
+				return err
+			}
+			if target.nth != 1 {
+				// Without a target, --nth picks among all tables.
+				payload["nth"] = target.nth
+			}
+			if strings.TrimSpace(pathArg) != "" {
+				payload["path"] = pathArg
+			}
+			if maxTokens > 0 {
+				payload["max_tokens"] = maxTokens
+			}
+			return runWithPage(pageName, "table", payload)
+		},
+	}
+
+	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
+	target.bind(cmd)
+	cmd.Flags().IntVar(&timeout, "timeout-ms", 5_000, "Timeout ms")
+	cmd.Flags().BoolVar(&expandSpans, "expand-spans", true, "Repeat colspan/rowspan cells")
+	cmd.Flags().IntVar(&maxRows, "max-rows", 500, "Max rows (0 = no limit)")
+	cmd.Flags().StringVar(&format, "format", "json", "Format (json|csv)")
+	cmd.Flags().StringVar(&pathArg, "path", "", "CSV output path")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Approximate token budget for JSON rows (0 = off)")
+	cmd.Flags().Bool("no-expand-spans", false, "Keep spanned cells once and leave the slots they cover empty")
+
+	return cmd
+}
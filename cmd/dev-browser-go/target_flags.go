@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// targetFlags are the element target flags shared by every command that
+// acts on one element. They map onto the tool's target keys.
+type targetFlags struct {
+	ref         string
+	selector    string
+	xpath       string
+	text        string
+	label       string
+	placeholder string
+	testID      string
+	ariaRole    string
+	ariaName    string
+	exact       bool
+	nth         int
+	within      string
+	withinRef   string
+}
+
+// bind registers the target flags; skip names flags the command already
+// uses for something else (e.g. wait's --text).
+func (f *targetFlags) bind(cmd *cobra.Command, skip ...string) {
+	skipped := map[string]bool{}
+	for _, name := range skip {
+		skipped[name] = true
+	}
+	strs := []struct {
+		name  string
+		value *string
+		usage string
+	}{
+		{"ref", &f.ref, "Target element ref"},
+		{"selector", &f.selector, "Target CSS selector (or a snapshot --with-locators locator)"},
+		{"xpath", &f.xpath, "Target XPath"},
+		{"text", &f.text, "Target element by its text"},
+		{"label", &f.label, "Target form control by its label"},
+		{"placeholder", &f.placeholder, "Target input by its placeholder"},
+		{"test-id", &f.testID, "Target element by data-testid"},
+		{"aria-role", &f.ariaRole, "Target ARIA role"},
+		{"aria-name", &f.ariaName, "ARIA name (with --aria-role)"},
+		{"within", &f.within, "Only look inside the element matching this selector"},
+		{"within-ref", &f.withinRef, "Only look inside this ref"},
+	}
+	for _, s := range strs {
+		if !skipped[s.name] {
+			cmd.Flags().StringVar(s.value, s.name, "", s.usage)
+		}
+	}
+	cmd.Flags().BoolVar(&f.exact, "exact", false, "Match --text/--label/--placeholder/--aria-name exactly")
+	cmd.Flags().IntVar(&f.nth, "nth", 1, "1-based match index")
+}
+
+// target returns the target keys set on the command line, or nil when no
+// target flag was given.
+func (f *targetFlags) target() (map[string]interface{}, error) {
+	target := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"ref":         f.ref,
+		"selector":    f.selector,
+		"xpath":       f.xpath,
+		"text":        f.text,
+		"label":       f.label,
+		"placeholder": f.placeholder,
+		"test_id":     f.testID,
+		"aria_role":   f.ariaRole,
+		"aria_name":   f.ariaName,
+	} {
+		if strings.TrimSpace(value) != "" {
+			target[key] = value
+		}
+	}
+	if len(target) == 0 {
+		if f.within != "" || f.withinRef != "" {
+			return nil, usageErrorf("--within needs a target to look for inside it")
+		}
+		return nil, nil
+	}
+	switch {
+	case f.within != "" && f.withinRef != "":
+		return nil, usageErrorf("use --within or --within-ref, not both")
+	case f.within != "":
+		target["within"] = map[string]interface{}{"selector": f.within}
+	case f.withinRef != "":
+		target["within"] = map[string]interface{}{"ref": f.withinRef}
+	}
+	if f.exact {
+		target["exact"] = true
+	}
+	if f.nth != 1 {
+		target["nth"] = f.nth
+	}
+	return target, nil
+}
+
+// apply adds the target to payload. Keys go in flat unless one collides
+// with an argument the tool already has (fill-ref's text), in which case
+// the target is passed as a "target" object.
+func (f *targetFlags) apply(payload map[string]interface{}) (bool, error) {
+	target, err := f.target()
+	if err != nil || target == nil {
+		return false, err
+	}
+	for key := range target {
+		if _, ok := payload[key]; ok {
+			payload["target"] = target
+			return true, nil
+		}
+	}
+	for key, value := range target {
+		payload[key] = value
+	}
+	return true, nil
+}
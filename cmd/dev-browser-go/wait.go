@@ -10,27 +10,84 @@ func newWaitCmd() *cobra.Command {
 	var state string
 	var timeout int
 	var minWait int
+	var stableFrames int
+	var idleMs int
+	var maxRequestMs int
+	var ignore []string
+	var target targetFlags
+	var text string
+	var urlMatches string
+	var function string
+	var consoleMatch string
+	var response string
+	var status string
+	var since int
+	var sinceMS int
 
 	cmd := &cobra.Command{
 		Use:   "wait",
-		Short: "Wait for page state",
+		Short: "Wait for page state or a condition",
 		Args:  cobra.NoArgs,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			payload := map[string]interface{}{
 				"strategy":    strategy,
-				"state":       state,
 				"timeout_ms":  timeout,
 				"min_wait_ms": minWait,
 			}
+			switch strategy {
+			case "stable":
+				payload["stable_frames"] = stableFrames
+			case "network":
+				payload["idle_ms"] = idleMs
+				payload["max_request_ms"] = maxRequestMs
+			}
+			if len(ignore) > 0 {
+				payload["ignore"] = ignore
+			}
+			for key, value := range map[string]string{
+				"state":         state,
+				"text":          text,
+				"url_matches":   urlMatches,
+				"function":      function,
+				"console_match": consoleMatch,
+				"response":      response,
+				"status":        status,
+			} {
+				if value != "" {
+					payload[key] = value
+				}
+			}
+			if since > 0 {
+				payload["since"] = since
+			}
+			if sinceMS > 0 {
+				payload["since_ms"] = sinceMS
+			}
+			if _, err := target.apply(payload); err != nil {
+				return err
+			}
 			return runWithPage(pageName, "wait", payload)
 		},
 	}
 
 	cmd.Flags().StringVar(&pageName, "page", "main", "Page name")
-	cmd.Flags().StringVar(&strategy, "strategy", "playwright", "Strategy")
-	cmd.Flags().StringVar(&state, "state", "load", "State")
+	cmd.Flags().StringVar(&strategy, "strategy", "playwright", "Strategy: playwright|perf|network|stable")
+	cmd.Flags().StringVar(&state, "state", "", "Load state (default load) or element state with a target: visible|hidden|attached|detached|enabled|disabled|editable (default visible)")
 	cmd.Flags().IntVar(&timeout, "timeout-ms", 10_000, "Timeout ms")
 	cmd.Flags().IntVar(&minWait, "min-wait-ms", 0, "Min wait ms")
+	cmd.Flags().IntVar(&stableFrames, "stable-frames", 10, "Quiet animation frames required by --strategy stable")
+	cmd.Flags().IntVar(&idleMs, "idle-ms", 500, "Quiet window required by --strategy network")
+	cmd.Flags().IntVar(&maxRequestMs, "max-request-ms", 10_000, "Ignore requests in flight longer than this (long-polls) for --strategy network")
+	cmd.Flags().StringArrayVar(&ignore, "ignore", nil, "URL substring to ignore for perf/network strategies (repeatable; replaces the default ad/analytics list)")
+	target.bind(cmd, "text")
+	cmd.Flags().StringVar(&text, "text", "", "Wait for visible text")
+	cmd.Flags().StringVar(&urlMatches, "url-matches", "", "Wait for page URL to match regex")
+	cmd.Flags().StringVar(&function, "function", "", "Wait for JS expression to be truthy")
+	cmd.Flags().StringVar(&consoleMatch, "console-match", "", "Wait for a console message matching regex")
+	cmd.Flags().StringVar(&response, "response", "", "Wait for a response whose URL matches glob")
+	cmd.Flags().StringVar(&status, "status", "", "Required response status (e.g. 200, 2xx)")
+	cmd.Flags().IntVar(&since, "since", 0, "With --console-match, only messages after this console id")
+	cmd.Flags().IntVar(&sinceMS, "since-ms", 0, "With --response, only responses finished at or after this time (epoch ms)")
 
 	return cmd
 }
@@ -17,7 +17,7 @@
           inherit version;
           src = self;
           subPackages = [ "cmd/dev-browser-go" ];
-          vendorHash = "sha256-pggjWBdRjfZ5Qs/i3bubhyRAvXFqYg2BAiQ3j7ov9tU=";
+          vendorHash = "sha256-0gyYUtETW6ed6KQx2FXyKEdQX2pbLxR/LGxjWf3Dcms=";
           ldflags = [ "-s" "-w" ];
           nativeBuildInputs = [ pkgs.makeWrapper ];
           postInstall = ''
@@ -0,0 +1,384 @@
+package devbrowser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const (
+	defaultRetryBackoffMs = 500
+	defaultMaxRepeats     = 10
+)
+
+type ActionsOptions struct {
+	CallOptions
+	// Vars are available to steps as ${vars.name}.
+	Vars map[string]interface{}
+}
+
+// ActionsResult holds one entry per step that ran (or was skipped), also
+// when the batch failed part way.
+type ActionsResult struct {
+	Results    []map[string]interface{}
+	Snapshot   string
+	FailedStep int
+}
+
+// ActionStep is one entry of an actions batch: a tool call plus its error
+// policy and control flow.
+type ActionStep struct {
+	ID               string
+	Name             string
+	Args             map[string]interface{}
+	ContinueOnError  bool
+	Retry            int
+	BackoffMs        int
+	If               interface{}
+	RepeatUntil      interface{}
+	MaxRepeats       int
+	RepeatIntervalMs int
+}
+
+// parseActionStep reads {"name", "arguments", "id", "continue_on_error",
+// "retry", "if", "repeat_until", "max_repeats", "repeat_interval_ms"}.
+// retry is a count or {"count", "backoff_ms"}; the backoff doubles per
+// attempt.
+func parseActionStep(call map[string]interface{}) (ActionStep, error) {
+	step := ActionStep{BackoffMs: defaultRetryBackoffMs, MaxRepeats: defaultMaxRepeats}
+	nameVal, ok := call["name"]
+	if !ok {
+		return step, invalidArgf("each call must include name")
+	}
+	name, ok := nameVal.(string)
+	if !ok || strings.TrimSpace(name) == "" {
+		return step, invalidArgf("each call must include non-empty string 'name'")
+	}
+	step.Name = name
+	argsVal, ok := call["arguments"]
+	if !ok || argsVal == nil {
+		argsVal = map[string]interface{}{}
+	}
+	if step.Args, ok = argsVal.(map[string]interface{}); !ok {
+		return step, invalidArgf("call 'arguments' must be an object")
+	}
+
+	var err error
+	if step.ID, err = optionalString(call, "id", ""); err != nil {
+		return step, err
+	}
+	if _, err := strconv.Atoi(step.ID); err == nil {
+		return step, invalidArgf("step id '%s' must not be a number (steps are also addressed by index)", step.ID)
+	}
+	if step.ContinueOnError, err = optionalBool(call, "continue_on_error", false); err != nil {
+		return step, err
+	}
+	switch raw := call["retry"].(type) {
+	case nil:
+	case map[string]interface{}:
+		if step.Retry, err = optionalInt(raw, "count", 0); err != nil {
+			return step, err
+		}
+		if step.BackoffMs, err = optionalInt(raw, "backoff_ms", defaultRetryBackoffMs); err != nil {
+			return step, err
+		}
+	default:
+		count, ok := asInt(raw)
+		if !ok || count < 0 {
+			return step, invalidArgf("retry must be a non-negative integer or {count, backoff_ms}")
+		}
+		step.Retry = count
+	}
+	step.If = call["if"]
+	step.RepeatUntil = call["repeat_until"]
+	if step.MaxRepeats, err = optionalInt(call, "max_repeats", defaultMaxRepeats); err != nil {
+		return step, err
+	}
+	if step.MaxRepeats < 1 {
+		return step, invalidArgf("max_repeats must be >= 1")
+	}
+	if step.RepeatIntervalMs, err = optionalInt(call, "repeat_interval_ms", 0); err != nil {
+		return step, err
+	}
+	return step, nil
+}
+
+// retryDelay is the wait before retry attempt n (1-based).
+func (s ActionStep) retryDelay(attempt int) time.Duration {
+	return time.Duration(s.BackoffMs) * time.Millisecond << (attempt - 1)
+}
+
+// RunActions runs calls in order. Each step may reference earlier steps as
+// ${steps.<id or index>.result...} and the caller's ${vars...}. On failure
+// the partial results are returned along with the error, whose details carry
+// failed_step.
+func RunActions(page playwright.Page, calls []map[string]interface{}, opts ActionsOptions) (ActionsResult, error) {
+	out := ActionsResult{Results: []map[string]interface{}{}, FailedStep: -1}
+	steps := map[string]interface{}{}
+	vars := opts.Vars
+	if vars == nil {
+		vars = map[string]interface{}{}
+	}
+	scope := map[string]interface{}{"steps": steps, "vars": vars}
+
+	for i, call := range calls {
+		fail := func(err error) (ActionsResult, error) {
+			out.FailedStep = i
+			te := ClassifyError(err)
+			details := map[string]interface{}{"failed_step": i}
+			for k, v := range te.Details {
+				details[k] = v
+			}
+			if name, ok := call["name"].(string); ok {
+				details["name"] = name
+			}
+			if id, ok := call["id"].(string); ok && id != "" {
+				details["id"] = id
+			}
+			return out, &ToolError{Code: te.Code, Message: fmt.Sprintf("step %d: %s", i, te.Message), Details: details, Err: err}
+		}
+
+		step, err := parseActionStep(call)
+		if err != nil {
+			return fail(err)
+		}
+		entry := map[string]interface{}{"name": step.Name}
+		if step.ID != "" {
+			entry["id"] = step.ID
+		}
+		started := time.Now()
+		record := func() {
+			entry["duration_ms"] = time.Since(started).Milliseconds()
+			out.Results = append(out.Results, entry)
+			steps[strconv.Itoa(i)] = entry
+			if step.ID != "" {
+				steps[step.ID] = entry
+			}
+		}
+
+		if step.If != nil {
+			run, err := evalStepCondition(page, step.If, scope, opts.Events)
+			if err != nil {
+				return fail(err)
+			}
+			if !run {
+				entry["ok"] = true
+				entry["skipped"] = true
+				record()
+				continue
+			}
+		}
+
+		res, attempts, repeats, err := runActionStep(page, step, scope, opts.CallOptions)
+		if attempts > 1 {
+			entry["attempts"] = attempts
+		}
+		if step.RepeatUntil != nil {
+			entry["repeats"] = repeats
+		}
+		if err != nil {
+			te := ClassifyError(err)
+			entry["ok"] = false
+			entry["error"] = te
+			record()
+			if step.ContinueOnError {
+				continue
+			}
+			return fail(err)
+		}
+		entry["ok"] = true
+		entry["result"] = res
+		record()
+		if snap, ok := res["snapshot"].(string); ok {
+			out.Snapshot = snap
+		}
+	}
+	return out, nil
+}
+
+// runActionStep runs one step with its retry policy and, when set, repeats it
+// until repeat_until holds; the condition sees the latest run as ${result}.
+// It returns the last result, the attempts of the last run and how many runs
+// happened.
+func runActionStep(page playwright.Page, step ActionStep, scope map[string]interface{}, opts CallOptions) (RunResult, int, int, error) {
+	for repeats := 1; ; repeats++ {
+		var res RunResult
+		var err error
+		attempts := 0
+		for attempts <= step.Retry {
+			attempts++
+			var args interface{}
+			if args, err = interpolate(step.Args, scope); err != nil {
+				return nil, attempts, repeats, err
+			}
+			res, err = RunCall(page, step.Name, args.(map[string]interface{}), opts)
+			if err == nil || ClassifyError(err).Code == ErrInvalidArgument {
+				break
+			}
+			if attempts <= step.Retry {
+				page.WaitForTimeout(float64(step.retryDelay(attempts).Milliseconds()))
+			}
+		}
+		if err != nil || step.RepeatUntil == nil {
+			return res, attempts, repeats, err
+		}
+
+		condScope := map[string]interface{}{"steps": scope["steps"], "vars": scope["vars"], "result": res}
+		done, err := evalStepCondition(page, step.RepeatUntil, condScope, opts.Events)
+		if err != nil {
+			return nil, attempts, repeats, err
+		}
+		if done {
+			return res, attempts, repeats, nil
+		}
+		if repeats >= step.MaxRepeats {
+			return nil, attempts, repeats, NewToolError(ErrAssertionFailed, fmt.Errorf("repeat_until not met after %d runs of %s", repeats, step.Name), map[string]interface{}{"repeats": repeats})
+		}
+		if step.RepeatIntervalMs > 0 {
+			page.WaitForTimeout(float64(step.RepeatIntervalMs))
+		}
+	}
+}
+
+// evalStepCondition decides if/repeat_until. A boolean is used as is, a
+// string is interpolated and tested for truthiness, and an object is a set
+// of expect checks sampled once.
+func evalStepCondition(page playwright.Page, cond interface{}, scope map[string]interface{}, events PageEvents) (bool, error) {
+	resolved, err := interpolate(cond, scope)
+	if err != nil {
+		return false, err
+	}
+	switch val := resolved.(type) {
+	case map[string]interface{}:
+		checks, _, err := parseExpectChecks(val)
+		if err != nil {
+			return false, err
+		}
+		for _, check := range checks {
+			outcome, err := sampleExpectCheck(page, check, events)
+			if err != nil {
+				return false, err
+			}
+			if !outcome.OK {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		return truthy(val), nil
+	}
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case float64:
+		return val != 0
+	case int:
+		return val != 0
+	case string:
+		s := strings.ToLower(strings.TrimSpace(val))
+		return s != "" && s != "false" && s != "0" && s != "null"
+	case []interface{}:
+		return len(val) > 0
+	case map[string]interface{}:
+		return len(val) > 0
+	}
+	return true
+}
+
+var placeholderPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// interpolate replaces ${path} placeholders in strings nested anywhere in v.
+// A string that is exactly one placeholder takes the referenced value with
+// its type; otherwise values are formatted into the string.
+func interpolate(v interface{}, scope map[string]interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		if m := placeholderPattern.FindStringSubmatch(val); m != nil && m[0] == val {
+			return lookupPath(scope, m[1])
+		}
+		var lookupErr error
+		out := placeholderPattern.ReplaceAllStringFunc(val, func(ph string) string {
+			resolved, err := lookupPath(scope, ph[2:len(ph)-1])
+			if err != nil {
+				lookupErr = err
+				return ph
+			}
+			if s, ok := resolved.(string); ok {
+				return s
+			}
+			return fmt.Sprint(resolved)
+		})
+		return out, lookupErr
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			resolved, err := interpolate(item, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			resolved, err := interpolate(item, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// lookupPath resolves a dotted path such as steps.login.result.url. Results
+// are plain maps, so typed values (RunResult, structs) are normalised
+// through JSON first.
+func lookupPath(scope map[string]interface{}, path string) (interface{}, error) {
+	var cur interface{} = scope
+	for _, part := range strings.Split(strings.TrimSpace(path), ".") {
+		cur = normalizeValue(cur)
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			next, ok := node[part]
+			if !ok {
+				return nil, invalidArgf("unknown reference ${%s}: no '%s'", path, part)
+			}
+			cur = next
+		case []interface{}:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, invalidArgf("unknown reference ${%s}: bad index '%s'", path, part)
+			}
+			cur = node[idx]
+		default:
+			return nil, invalidArgf("unknown reference ${%s}: cannot index into '%s'", path, part)
+		}
+	}
+	return normalizeValue(cur), nil
+}
+
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, string, bool, float64, map[string]interface{}, []interface{}:
+		return v
+	case RunResult:
+		return map[string]interface{}(val)
+	}
+	var out interface{}
+	if err := decodeJSResult(v, &out); err != nil {
+		return v
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package devbrowser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseActionStep(t *testing.T) {
+	step, err := parseActionStep(map[string]interface{}{
+		"name":              "click_ref",
+		"id":                "submit",
+		"arguments":         map[string]interface{}{"ref": "e3"},
+		"continue_on_error": true,
+		"retry":             map[string]interface{}{"count": float64(2), "backoff_ms": float64(100)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step.ID != "submit" || !step.ContinueOnError || step.Retry != 2 || step.BackoffMs != 100 {
+		t.Fatalf("unexpected step %+v", step)
+	}
+	if step.retryDelay(1) != 100*time.Millisecond || step.retryDelay(3) != 400*time.Millisecond {
+		t.Fatalf("backoff should double: %v, %v", step.retryDelay(1), step.retryDelay(3))
+	}
+
+	step, err = parseActionStep(map[string]interface{}{"name": "press", "retry": float64(3)})
+	if err != nil || step.Retry != 3 || step.BackoffMs != defaultRetryBackoffMs || step.MaxRepeats != defaultMaxRepeats {
+		t.Fatalf("unexpected defaults %+v, %v", step, err)
+	}
+
+	bad := []map[string]interface{}{
+		{},
+		{"name": ""},
+		{"name": "press", "arguments": "Enter"},
+		{"name": "press", "id": "2"},
+		{"name": "press", "retry": "twice"},
+		{"name": "press", "max_repeats": float64(0)},
+	}
+	for _, call := range bad {
+		if _, err := parseActionStep(call); err == nil {
+			t.Fatalf("expected error for %v", call)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	scope := map[string]interface{}{
+		"vars": map[string]interface{}{"user": "ada"},
+		"steps": map[string]interface{}{
+			"login": map[string]interface{}{
+				"ok":     true,
+				"result": RunResult{"url": "https://app.test/home", "items": []map[string]interface{}{{"ref": "e7"}}},
+			},
+		},
+	}
+
+	got, err := interpolate(map[string]interface{}{
+		"text":  "hello ${vars.user}",
+		"ref":   "${steps.login.result.items.0.ref}",
+		"ok":    "${steps.login.ok}",
+		"plain": float64(3),
+		"list":  []interface{}{"${vars.user}"},
+	}, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := got.(map[string]interface{})
+	if m["text"] != "hello ada" || m["ref"] != "e7" || m["ok"] != true || m["plain"] != float64(3) {
+		t.Fatalf("unexpected interpolation %v", m)
+	}
+	if list := m["list"].([]interface{}); list[0] != "ada" {
+		t.Fatalf("unexpected list interpolation %v", list)
+	}
+
+	for _, bad := range []string{"${steps.signup.result}", "${steps.login.result.items.5.ref}", "x ${vars.missing}"} {
+		if _, err := interpolate(bad, scope); err == nil {
+			t.Fatalf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestTruthy(t *testing.T) {
+	for _, v := range []interface{}{true, "yes", float64(1), []interface{}{1}, map[string]interface{}{"a": 1}} {
+		if !truthy(v) {
+			t.Fatalf("%v should be truthy", v)
+		}
+	}
+	for _, v := range []interface{}{nil, false, "", "false", "0", "null", float64(0), []interface{}{}} {
+		if truthy(v) {
+			t.Fatalf("%v should be falsy", v)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package devbrowser
+
+import (
+	"github.com/playwright-community/playwright-go"
+)
+
+// afterActionTools accept snapshot_after and wait_after.
+var afterActionTools = map[string]bool{
+	"click_ref": true,
+	"fill_ref":  true,
+	"press":     true,
+}
+
+// afterAction is what to do once an interaction succeeded: wait for a
+// condition, then take a fresh snapshot, saving the caller a round trip.
+type afterAction struct {
+	Wait     map[string]interface{}
+	Snapshot map[string]interface{}
+}
+
+// parseAfterAction reads wait_after (wait tool args) and snapshot_after
+// (true or snapshot tool args). It returns nil when neither is set.
+func parseAfterAction(args map[string]interface{}) (*afterAction, error) {
+	after := &afterAction{}
+	if raw, ok := args["wait_after"]; ok && raw != nil {
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, invalidArgf("expected object for 'wait_after'")
+		}
+		after.Wait = obj
+	}
+	switch raw := args["snapshot_after"].(type) {
+	case nil:
+	case bool:
+		if raw {
+			after.Snapshot = map[string]interface{}{}
+		}
+	case map[string]interface{}:
+		after.Snapshot = raw
+	default:
+		return nil, invalidArgf("expected boolean or object for 'snapshot_after'")
+	}
+	if after.Wait == nil && after.Snapshot == nil {
+		return nil, nil
+	}
+	return after, nil
+}
+
+// run performs the wait and snapshot and adds them to res. The wait result
+// goes under "wait"; snapshot fields are merged in so summary output prints
+// the snapshot, as it does for the snapshot tool.
+func (a *afterAction) run(page playwright.Page, res RunResult, opts CallOptions) {
+	a.apply(res,
+		func() (RunResult, error) { return runCall(page, "wait", a.Wait, opts) },
+		func() (RunResult, error) { return runSnapshot(page, a.Snapshot) },
+	)
+}
+
+// apply adds the wait and snapshot results to res. The action already
+// happened, so a failure is reported next to its result instead of failing
+// the call: a wait error as "wait": {"ok": false, "error": ...}, a snapshot
+// error as "snapshot_error".
+func (a *afterAction) apply(res RunResult, wait, snapshot func() (RunResult, error)) {
+	if a.Wait != nil {
+		waitRes, err := wait()
+		if err != nil {
+			res["wait"] = map[string]interface{}{"ok": false, "error": ClassifyError(err)}
+		} else {
+			res["wait"] = waitRes
+		}
+	}
+	if a.Snapshot == nil {
+		return
+	}
+	snapRes, err := snapshot()
+	if err != nil {
+		res["snapshot_error"] = ClassifyError(err)
+		return
+	}
+	for key, value := range snapRes {
+		if _, exists := res[key]; !exists {
+			res[key] = value
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package devbrowser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAfterAction(t *testing.T) {
+	after, err := parseAfterAction(map[string]interface{}{"ref": "e3"})
+	if err != nil || after != nil {
+		t.Fatalf("expected no after action, got %+v, %v", after, err)
+	}
+
+	after, err = parseAfterAction(map[string]interface{}{"snapshot_after": false})
+	if err != nil || after != nil {
+		t.Fatalf("snapshot_after=false should be a no-op, got %+v, %v", after, err)
+	}
+
+	after, err = parseAfterAction(map[string]interface{}{"snapshot_after": true})
+	if err != nil || after == nil || after.Snapshot == nil || after.Wait != nil {
+		t.Fatalf("unexpected after action %+v, %v", after, err)
+	}
+
+	after, err = parseAfterAction(map[string]interface{}{
+		"snapshot_after": map[string]interface{}{"max_items": float64(20)},
+		"wait_after":     map[string]interface{}{"text": "Saved"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Snapshot["max_items"] != float64(20) || after.Wait["text"] != "Saved" {
+		t.Fatalf("unexpected after action %+v", after)
+	}
+
+	if _, err := parseAfterAction(map[string]interface{}{"snapshot_after": "yes"}); err == nil {
+		t.Fatal("expected error for string snapshot_after")
+	}
+	if _, err := parseAfterAction(map[string]interface{}{"wait_after": "stable"}); err == nil {
+		t.Fatal("expected error for string wait_after")
+	}
+}
+
+func TestAfterActionKeepsResultWhenAfterStepsFail(t *testing.T) {
+	after := &afterAction{Wait: map[string]interface{}{"text": "Saved"}, Snapshot: map[string]interface{}{}}
+	res := RunResult{"clicked": "e3"}
+	after.apply(res,
+		func() (RunResult, error) { return nil, NewToolError(ErrTimeout, errors.New("wait timed out"), nil) },
+		func() (RunResult, error) { return nil, NewToolError(ErrUnknown, errors.New("page closed"), nil) },
+	)
+	if res["clicked"] != "e3" {
+		t.Fatalf("action result lost: %+v", res)
+	}
+	wait, ok := res["wait"].(map[string]interface{})
+	if !ok || wait["ok"] != false {
+		t.Fatalf("expected failed wait entry, got %+v", res["wait"])
+	}
+	if te, ok := wait["error"].(*ToolError); !ok || te.Code != ErrTimeout {
+		t.Fatalf("expected timeout under wait.error, got %+v", wait["error"])
+	}
+	if te, ok := res["snapshot_error"].(*ToolError); !ok || te.Code != ErrUnknown {
+		t.Fatalf("expected snapshot_error, got %+v", res["snapshot_error"])
+	}
+}
+
+func TestAfterActionMergesSnapshotWithoutOverwriting(t *testing.T) {
+	after := &afterAction{Wait: map[string]interface{}{}, Snapshot: map[string]interface{}{}}
+	res := RunResult{"ref": "e3"}
+	after.apply(res,
+		func() (RunResult, error) { return RunResult{"ok": true}, nil },
+		func() (RunResult, error) { return RunResult{"ref": "e9", "snapshot": "e1: button"}, nil },
+	)
+	if res["ref"] != "e3" || res["snapshot"] != "e1: button" {
+		t.Fatalf("unexpected merge %+v", res)
+	}
+	if wait, ok := res["wait"].(RunResult); !ok || wait["ok"] != true {
+		t.Fatalf("expected wait result, got %+v", res["wait"])
+	}
+	if _, ok := res["snapshot_error"]; ok {
+		t.Fatalf("unexpected snapshot_error %+v", res)
+	}
+}
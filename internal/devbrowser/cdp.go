@@ -14,7 +14,7 @@ func OpenPage(wsEndpoint string, targetID string) (*playwright.Playwright, playw
 	browser, err := pw.Chromium.ConnectOverCDP(wsEndpoint)
 	if err != nil {
 		pw.Stop()
-		return nil, nil, nil, fmt.Errorf("connect over CDP: %w", err)
+		return nil, nil, nil, NewToolError(ErrDaemonUnavailable, fmt.Errorf("connect over CDP: %w", err), nil)
 	}
 	page, err := findPageByTargetID(browser, targetID)
 	if err != nil {
@@ -0,0 +1,84 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// clickDiagnosticsJS explains why el may not be clickable: visibility,
+// enabled state, viewport position and the topmost element at its center.
+const clickDiagnosticsJS = `(el) => {
+  const r = el.getBoundingClientRect();
+  const style = getComputedStyle(el);
+  const vw = innerWidth;
+  const vh = innerHeight;
+  const visible = r.width > 0 && r.height > 0 && style.visibility !== "hidden" && style.display !== "none";
+  const inViewport = r.bottom > 0 && r.right > 0 && r.top < vh && r.left < vw;
+  const disabled = el.matches(":disabled") || el.getAttribute("aria-disabled") === "true";
+  const cx = r.left + r.width / 2;
+  const cy = r.top + r.height / 2;
+
+  let blockedBy = null;
+  if (visible && inViewport) {
+    let hit = document.elementFromPoint(cx, cy);
+    while (hit && hit.shadowRoot) {
+      const inner = hit.shadowRoot.elementFromPoint(cx, cy);
+      if (!inner || inner === hit) break;
+      hit = inner;
+    }
+    if (hit && hit !== el && !el.contains(hit)) {
+      const describe = globalThis.__devBrowser_describeElement;
+      if (describe) {
+        const item = describe(hit);
+        blockedBy = { ref: item.ref, role: item.role, name: item.name, line: item.line };
+      } else {
+        blockedBy = { line: hit.tagName.toLowerCase() };
+      }
+    }
+  }
+  return {
+    visible,
+    enabled: !disabled,
+    in_viewport: inViewport,
+    pointer_events: style.pointerEvents,
+    center: { x: Math.round(cx), y: Math.round(cy) },
+    blocked_by: blockedBy,
+  };
+}`
+
+// clickFailure enriches a failed click on a target with actionability
+// diagnostics so the caller can see (and dismiss) what is in the way.
+func clickFailure(el playwright.ElementHandle, spec TargetSpec, clickErr error) error {
+	raw, err := el.Evaluate(clickDiagnosticsJS)
+	if err != nil {
+		return clickErr
+	}
+	diag, ok := raw.(map[string]interface{})
+	if !ok {
+		return clickErr
+	}
+	label := "ref " + spec.Ref
+	if strings.TrimSpace(spec.Ref) != "" {
+		diag["ref"] = spec.Ref
+	} else {
+		label = spec.describe()
+		diag["target"] = label
+	}
+
+	code := ClassifyError(clickErr).Code
+	msg := clickErr.Error()
+	if visible, _ := diag["visible"].(bool); !visible {
+		code = ErrNotVisible
+		msg = fmt.Sprintf("%s is not visible: %s", label, msg)
+	} else if inViewport, _ := diag["in_viewport"].(bool); !inViewport {
+		msg = fmt.Sprintf("%s is outside the viewport: %s", label, msg)
+	} else if enabled, _ := diag["enabled"].(bool); !enabled {
+		msg = fmt.Sprintf("%s is disabled: %s", label, msg)
+	} else if blocker, ok := diag["blocked_by"].(map[string]interface{}); ok {
+		line, _ := blocker["line"].(string)
+		msg = fmt.Sprintf("%s is covered by %s: %s", label, line, msg)
+	}
+	return &ToolError{Code: code, Message: msg, Details: diag, Err: clickErr}
+}
@@ -170,15 +170,15 @@ func StopDaemon(profile string) (bool, error) {
 
 func EnsurePage(profile string, headless bool, page string, window *WindowSize) (string, string, error) {
 	if err := StartDaemon(profile, headless, window); err != nil {
-		return "", "", err
+		return "", "", NewToolError(ErrDaemonUnavailable, err, nil)
 	}
 	base := DaemonBaseURL(profile)
 	if base == "" {
-		return "", "", errors.New("daemon state missing after start")
+		return "", "", NewToolError(ErrDaemonUnavailable, errors.New("daemon state missing after start"), nil)
 	}
 	data, err := HTTPJSON(http.MethodPost, base+"/pages", map[string]any{"name": page}, 10*time.Second)
 	if err != nil {
-		return "", "", err
+		return "", "", NewToolError(ErrDaemonUnavailable, err, nil)
 	}
 	ws, _ := data["wsEndpoint"].(string)
 	tid, _ := data["targetId"].(string)
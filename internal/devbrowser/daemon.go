@@ -195,15 +195,21 @@ func (d *Daemon) handlePageSubresource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(parts) != 2 || parts[1] != "console" {
+	if len(parts) != 2 || r.Method != http.MethodGet {
 		d.writeJSON(w, http.StatusNotFound, map[string]any{"ok": false, "error": "not found"})
 		return
 	}
-	if r.Method != http.MethodGet {
+	switch parts[1] {
+	case "console":
+		d.handleConsole(w, r, name)
+	case "network":
+		d.handleNetwork(w, name)
+	default:
 		d.writeJSON(w, http.StatusNotFound, map[string]any{"ok": false, "error": "not found"})
-		return
 	}
+}
 
+func (d *Daemon) handleConsole(w http.ResponseWriter, r *http.Request, name string) {
 	query := r.URL.Query()
 	since := int64(0)
 	if raw := strings.TrimSpace(query.Get("since")); raw != "" {
@@ -223,6 +229,15 @@ func (d *Daemon) handlePageSubresource(w http.ResponseWriter, r *http.Request) {
 		}
 		limit = val
 	}
+	maxTokens := 0
+	if raw := strings.TrimSpace(query.Get("max_tokens")); raw != "" {
+		val, err := strconv.Atoi(raw)
+		if err != nil || val < 0 {
+			d.writeJSON(w, http.StatusBadRequest, map[string]any{"ok": false, "error": "invalid max_tokens"})
+			return
+		}
+		maxTokens = val
+	}
 
 	levelFilter, err := parseConsoleLevels(query.Get("levels"))
 	if err != nil {
@@ -240,17 +255,39 @@ func (d *Daemon) handlePageSubresource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	logs = selectConsoleLogs(logs, levelFilter, since, limit)
+	logs = fitConsoleLogs(logs, since, maxTokens)
 	if len(logs) > 0 {
 		lastID = logs[len(logs)-1].ID
 	}
 
+	payload := map[string]any{
+		"ok":               true,
+		"page":             name,
+		"since":            since,
+		"limit":            limit,
+		"last_id":          lastID,
+		"logs":             logs,
+		"estimated_tokens": jsonTokens(logs),
+	}
+	if maxTokens > 0 {
+		payload["max_tokens"] = maxTokens
+	}
+	d.writeJSON(w, http.StatusOK, payload)
+}
+
+func (d *Daemon) handleNetwork(w http.ResponseWriter, name string) {
+	act, err := d.host.NetworkActivity(name)
+	if err != nil {
+		d.writeJSON(w, http.StatusNotFound, map[string]any{"ok": false, "error": err.Error()})
+		return
+	}
 	d.writeJSON(w, http.StatusOK, map[string]any{
-		"ok":      true,
-		"page":    name,
-		"since":   since,
-		"limit":   limit,
-		"last_id": lastID,
-		"logs":    logs,
+		"ok":         true,
+		"page":       name,
+		"now_ms":     act.NowMS,
+		"inflight":   act.Inflight,
+		"recent":     act.Recent,
+		"websockets": act.Sockets,
 	})
 }
 
@@ -265,6 +302,17 @@ func selectConsoleLogs(logs []ConsoleEntry, filter consoleLevelFilter, since int
 	return entries[len(entries)-limit:]
 }
 
+// fitConsoleLogs keeps as many entries as fit maxTokens from the same end
+// selectConsoleLogs keeps: the oldest when paging with since (so last_id
+// resumes where the budget ran out), otherwise the newest.
+func fitConsoleLogs(logs []ConsoleEntry, since int64, maxTokens int) []ConsoleEntry {
+	if since > 0 {
+		return logs[:countWithinTokens(len(logs), maxTokens, func(i int) int { return jsonTokens(logs[i]) })]
+	}
+	n := countWithinTokens(len(logs), maxTokens, func(i int) int { return jsonTokens(logs[len(logs)-1-i]) })
+	return logs[len(logs)-n:]
+}
+
 func (d *Daemon) writeJSON(w http.ResponseWriter, status int, payload map[string]any) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
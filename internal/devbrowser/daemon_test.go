@@ -1,6 +1,9 @@
 package devbrowser
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func TestSelectConsoleLogs_SinceAndLimit(t *testing.T) {
 	allEntries := []ConsoleEntry{
@@ -33,3 +36,19 @@ func TestSelectConsoleLogs_SinceAndLimit(t *testing.T) {
 		t.Fatalf("unexpected entries without since: %+v", noSince)
 	}
 }
+
+func TestFitConsoleLogsKeepsPagingEnd(t *testing.T) {
+	logs := []ConsoleEntry{}
+	for i := 1; i <= 10; i++ {
+		logs = append(logs, ConsoleEntry{ID: int64(i), Type: "log", Text: fmt.Sprintf("message %d", i)})
+	}
+	budget := jsonTokens(logs[0]) * 3
+	newest := fitConsoleLogs(logs, 0, budget)
+	if len(newest) == 0 || newest[len(newest)-1].ID != 10 {
+		t.Fatalf("expected the newest entries, got %v", newest)
+	}
+	oldest := fitConsoleLogs(logs, 5, budget)
+	if len(oldest) == 0 || oldest[0].ID != 1 || len(oldest) == len(logs) {
+		t.Fatalf("expected the oldest entries, got %v", oldest)
+	}
+}
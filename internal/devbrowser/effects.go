@@ -0,0 +1,298 @@
+package devbrowser
+
+import (
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const (
+	defaultSettleMs    = 500
+	maxEffectRequests  = 50
+	maxEffectConsole   = 50
+	effectConsoleLevel = "error"
+)
+
+// focusedElementJS describes document.activeElement, or null when focus is
+// on the body.
+const focusedElementJS = `() => {
+  let el = document.activeElement;
+  while (el && el.shadowRoot && el.shadowRoot.activeElement) el = el.shadowRoot.activeElement;
+  if (!el || el === document.body || el === document.documentElement) return null;
+  const describe = globalThis.__devBrowser_describeElement;
+  if (!describe) return { line: el.tagName.toLowerCase() };
+  const item = describe(el);
+  return { ref: item.ref, role: item.role, name: item.name, line: item.line };
+}`
+
+type EffectRequest struct {
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	ResourceType string `json:"resource_type,omitempty"`
+	Status       int    `json:"status,omitempty"`
+	Failed       string `json:"failed,omitempty"`
+}
+
+type EffectDialog struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// effectsRecorder watches a page while an action runs and for a short
+// settle window afterwards.
+type effectsRecorder struct {
+	page     playwright.Page
+	events   PageEvents
+	settleMs int
+	// listeners are the handlers exactly as registered, so stop removes
+	// these registrations and no others.
+	listeners []effectListener
+
+	mu            sync.Mutex
+	urlBefore     string
+	navigations   []string
+	popups        []playwright.Page
+	dialogs       []EffectDialog
+	requests      []EffectRequest
+	requestIndex  map[playwright.Request]int
+	requestsTotal int
+	console       []ConsoleEntry
+	consoleSince  int64
+	focusBefore   map[string]interface{}
+}
+
+type effectListener struct {
+	event   string
+	handler interface{}
+}
+
+// parseEffectsOptions reads report_effects and settle_ms.
+func parseEffectsOptions(args map[string]interface{}) (bool, int, error) {
+	enabled, err := optionalBool(args, "report_effects", false)
+	if err != nil {
+		return false, 0, err
+	}
+	settleMs, err := optionalInt(args, "settle_ms", defaultSettleMs)
+	if err != nil {
+		return false, 0, err
+	}
+	if settleMs < 0 {
+		return false, 0, invalidArgf("settle_ms must be >= 0")
+	}
+	return enabled, settleMs, nil
+}
+
+// startEffects begins recording when args ask for report_effects; it
+// returns nil otherwise. Call finish after the action to collect the report.
+// Console errors come from events when set, since the daemon also sees
+// messages logged between the CLI's calls.
+func startEffects(page playwright.Page, args map[string]interface{}, events PageEvents) (*effectsRecorder, error) {
+	enabled, settleMs, err := parseEffectsOptions(args)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	r := &effectsRecorder{
+		page:         page,
+		events:       events,
+		settleMs:     settleMs,
+		urlBefore:    page.URL(),
+		requestIndex: make(map[playwright.Request]int),
+	}
+	r.focusBefore = focusedElement(page)
+	if events != nil {
+		if _, lastID, err := events.ConsoleSince(0, "all", 1); err == nil {
+			r.consoleSince = lastID
+		}
+	} else {
+		r.listen("console", func(msg playwright.ConsoleMessage) { r.onConsole(msg) })
+		r.listen("pageerror", func(err error) { r.onPageError(err) })
+	}
+	r.listen("framenavigated", func(frame playwright.Frame) { r.onFrameNavigated(frame) })
+	r.listen("popup", func(popup playwright.Page) { r.onPopup(popup) })
+	r.listen("dialog", func(dialog playwright.Dialog) { r.onDialog(dialog) })
+	r.listen("request", func(req playwright.Request) { r.onRequest(req) })
+	r.listen("response", func(resp playwright.Response) { r.onResponse(resp) })
+	r.listen("requestfailed", func(req playwright.Request) { r.onRequestFailed(req) })
+	return r, nil
+}
+
+func (r *effectsRecorder) listen(event string, handler interface{}) {
+	r.page.On(event, handler)
+	r.listeners = append(r.listeners, effectListener{event: event, handler: handler})
+}
+
+func (r *effectsRecorder) onFrameNavigated(frame playwright.Frame) {
+	if frame.ParentFrame() != nil {
+		return
+	}
+	r.mu.Lock()
+	r.navigations = append(r.navigations, frame.URL())
+	r.mu.Unlock()
+}
+
+func (r *effectsRecorder) onPopup(popup playwright.Page) {
+	r.mu.Lock()
+	r.popups = append(r.popups, popup)
+	r.mu.Unlock()
+}
+
+// onDialog records the dialog and dismisses it, which is what Playwright
+// does when nobody listens.
+func (r *effectsRecorder) onDialog(dialog playwright.Dialog) {
+	r.mu.Lock()
+	r.dialogs = append(r.dialogs, EffectDialog{Type: dialog.Type(), Message: dialog.Message()})
+	r.mu.Unlock()
+	_ = dialog.Dismiss()
+}
+
+func (r *effectsRecorder) onRequest(req playwright.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requestsTotal++
+	if len(r.requests) >= maxEffectRequests {
+		return
+	}
+	r.requestIndex[req] = len(r.requests)
+	r.requests = append(r.requests, EffectRequest{Method: req.Method(), URL: req.URL(), ResourceType: req.ResourceType()})
+}
+
+func (r *effectsRecorder) onResponse(resp playwright.Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if idx, ok := r.requestIndex[resp.Request()]; ok {
+		r.requests[idx].Status = resp.Status()
+	}
+}
+
+func (r *effectsRecorder) onRequestFailed(req playwright.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	idx, ok := r.requestIndex[req]
+	if !ok {
+		return
+	}
+	r.requests[idx].Failed = "failed"
+	if failure := req.Failure(); failure != nil {
+		r.requests[idx].Failed = failure.Error()
+	}
+}
+
+func (r *effectsRecorder) onConsole(msg playwright.ConsoleMessage) {
+	if consoleLevelForType(msg.Type()) != effectConsoleLevel {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.console) < maxEffectConsole {
+		r.console = append(r.console, ConsoleEntry{TimeMS: NowMS(), Type: msg.Type(), Text: msg.Text()})
+	}
+}
+
+func (r *effectsRecorder) onPageError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.console) < maxEffectConsole {
+		r.console = append(r.console, ConsoleEntry{TimeMS: NowMS(), Type: "pageerror", Text: err.Error()})
+	}
+}
+
+// stop removes the listeners; it is safe on a nil or already stopped
+// recorder.
+func (r *effectsRecorder) stop() {
+	if r == nil {
+		return
+	}
+	for _, l := range r.listeners {
+		r.page.RemoveListener(l.event, l.handler)
+	}
+	r.listeners = nil
+}
+
+// finish waits out the settle window, stops recording and returns the
+// report.
+func (r *effectsRecorder) finish() map[string]interface{} {
+	if r.settleMs > 0 {
+		r.page.WaitForTimeout(float64(r.settleMs))
+	}
+	r.stop()
+
+	urlAfter := r.page.URL()
+	focusAfter := focusedElement(r.page)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	popups := []map[string]interface{}{}
+	for _, popup := range r.popups {
+		popups = append(popups, map[string]interface{}{"url": popup.URL()})
+	}
+	console := r.console
+	if r.events != nil {
+		if logs, _, err := r.events.ConsoleSince(r.consoleSince, effectConsoleLevel, maxEffectConsole); err == nil {
+			console = logs
+		}
+	}
+	if console == nil {
+		console = []ConsoleEntry{}
+	}
+	dialogs := r.dialogs
+	if dialogs == nil {
+		dialogs = []EffectDialog{}
+	}
+	requests := r.requests
+	if requests == nil {
+		requests = []EffectRequest{}
+	}
+	navigations := r.navigations
+	if navigations == nil {
+		navigations = []string{}
+	}
+
+	return map[string]interface{}{
+		"settle_ms":      r.settleMs,
+		"navigated":      len(r.navigations) > 0 || urlAfter != r.urlBefore,
+		"url_before":     r.urlBefore,
+		"url_after":      urlAfter,
+		"navigations":    navigations,
+		"popups":         popups,
+		"dialogs":        dialogs,
+		"console_errors": console,
+		"requests":       requests,
+		"requests_total": r.requestsTotal,
+		"focus": map[string]interface{}{
+			"changed": focusChanged(r.focusBefore, focusAfter),
+			"before":  r.focusBefore,
+			"after":   focusAfter,
+		},
+	}
+}
+
+// focusedElement describes the focused element; errors (e.g. a page torn
+// down by navigation) report no focus.
+func focusedElement(page playwright.Page) map[string]interface{} {
+	if err := ensureInjected(page, "simple"); err != nil {
+		return nil
+	}
+	raw, err := page.Evaluate(focusedElementJS)
+	if err != nil {
+		return nil
+	}
+	desc, _ := raw.(map[string]interface{})
+	return desc
+}
+
+// focusChanged compares two focusedElement results by ref, falling back to
+// the rendered line for elements without one.
+func focusChanged(before, after map[string]interface{}) bool {
+	if before == nil || after == nil {
+		return (before == nil) != (after == nil)
+	}
+	refBefore, _ := before["ref"].(string)
+	refAfter, _ := after["ref"].(string)
+	if refBefore != "" || refAfter != "" {
+		return refBefore != refAfter
+	}
+	lineBefore, _ := before["line"].(string)
+	lineAfter, _ := after["line"].(string)
+	return lineBefore != lineAfter
+}
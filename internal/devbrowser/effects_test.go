@@ -0,0 +1,90 @@
+package devbrowser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+func TestParseEffectsOptions(t *testing.T) {
+	enabled, settle, err := parseEffectsOptions(map[string]interface{}{})
+	if err != nil || enabled || settle != defaultSettleMs {
+		t.Fatalf("unexpected defaults: %v %d %v", enabled, settle, err)
+	}
+
+	enabled, settle, err = parseEffectsOptions(map[string]interface{}{"report_effects": true, "settle_ms": float64(1200)})
+	if err != nil || !enabled || settle != 1200 {
+		t.Fatalf("unexpected options: %v %d %v", enabled, settle, err)
+	}
+
+	if _, _, err := parseEffectsOptions(map[string]interface{}{"report_effects": true, "settle_ms": float64(-1)}); err == nil {
+		t.Fatal("expected error for negative settle_ms")
+	}
+}
+
+func TestFocusChanged(t *testing.T) {
+	input := map[string]interface{}{"ref": "e3", "line": `- textbox "Email" [ref=e3]`}
+	button := map[string]interface{}{"ref": "e7", "line": `- button "Save" [ref=e7]`}
+
+	cases := []struct {
+		before, after map[string]interface{}
+		want          bool
+	}{
+		{nil, nil, false},
+		{nil, input, true},
+		{input, nil, true},
+		{input, input, false},
+		{input, button, true},
+		{map[string]interface{}{"line": "iframe"}, map[string]interface{}{"line": "iframe"}, false},
+		{map[string]interface{}{"line": "iframe"}, map[string]interface{}{"line": "video"}, true},
+	}
+	for i, tc := range cases {
+		if got := focusChanged(tc.before, tc.after); got != tc.want {
+			t.Fatalf("case %d: focusChanged = %v, want %v", i, got, tc.want)
+		}
+	}
+}
+
+// listenerPage records listeners the way playwright-go's emitter does,
+// keyed by the handler's function pointer.
+type listenerPage struct {
+	playwright.Page
+	handlers map[string][]interface{}
+}
+
+func (p *listenerPage) On(event string, handler interface{}) {
+	p.handlers[event] = append(p.handlers[event], handler)
+}
+
+func (p *listenerPage) RemoveListener(event string, handler interface{}) {
+	ptr := reflect.ValueOf(handler).Pointer()
+	kept := []interface{}{}
+	for _, h := range p.handlers[event] {
+		if reflect.ValueOf(h).Pointer() != ptr {
+			kept = append(kept, h)
+		}
+	}
+	p.handlers[event] = kept
+}
+
+func TestEffectsStopRemovesOnlyItsListeners(t *testing.T) {
+	page := &listenerPage{handlers: map[string][]interface{}{}}
+	other := func(playwright.Request) {}
+	page.On("request", other)
+
+	r := &effectsRecorder{page: page, requestIndex: map[playwright.Request]int{}}
+	r.listen("request", func(req playwright.Request) { r.onRequest(req) })
+	r.listen("popup", func(popup playwright.Page) { r.onPopup(popup) })
+	if len(page.handlers["request"]) != 2 || len(page.handlers["popup"]) != 1 {
+		t.Fatalf("unexpected listeners: %v", page.handlers)
+	}
+	r.stop()
+	r.stop()
+	if len(page.handlers["request"]) != 1 || len(page.handlers["popup"]) != 0 {
+		t.Fatalf("expected only the recorder's listeners removed: %v", page.handlers)
+	}
+	if reflect.ValueOf(page.handlers["request"][0]).Pointer() != reflect.ValueOf(other).Pointer() {
+		t.Fatal("removed another listener")
+	}
+}
@@ -0,0 +1,96 @@
+package devbrowser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type ErrorCode string
+
+const (
+	ErrRefNotFound       ErrorCode = "ref_not_found"
+	ErrRefStale          ErrorCode = "ref_stale"
+	ErrTimeout           ErrorCode = "timeout"
+	ErrNotVisible        ErrorCode = "not_visible"
+	ErrNavigationFailed  ErrorCode = "navigation_failed"
+	ErrInvalidArgument   ErrorCode = "invalid_argument"
+	ErrDaemonUnavailable ErrorCode = "daemon_unavailable"
+	ErrAssertionFailed   ErrorCode = "assertion_failed"
+	ErrNotFound          ErrorCode = "not_found"
+	ErrUnknown           ErrorCode = "error"
+)
+
+// exitCodes gives every error class its own process exit status; 1 is
+// left for unclassified failures.
+var exitCodes = map[ErrorCode]int{
+	ErrUnknown:           1,
+	ErrInvalidArgument:   2,
+	ErrRefNotFound:       3,
+	ErrRefStale:          4,
+	ErrNotVisible:        5,
+	ErrTimeout:           6,
+	ErrNavigationFailed:  7,
+	ErrDaemonUnavailable: 8,
+	ErrAssertionFailed:   9,
+	ErrNotFound:          10,
+}
+
+// ToolError is an error with a stable code agents can branch on.
+type ToolError struct {
+	Code    ErrorCode              `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	Err     error                  `json:"-"`
+}
+
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// NewToolError wraps err with code. details may be nil.
+func NewToolError(code ErrorCode, err error, details map[string]interface{}) *ToolError {
+	return &ToolError{Code: code, Message: err.Error(), Details: details, Err: err}
+}
+
+func invalidArgf(format string, a ...interface{}) error {
+	return &ToolError{Code: ErrInvalidArgument, Message: fmt.Sprintf(format, a...)}
+}
+
+// ExitCode maps an error code to the CLI exit status.
+func ExitCode(code ErrorCode) int {
+	if c, ok := exitCodes[code]; ok {
+		return c
+	}
+	return 1
+}
+
+// ClassifyError returns err as a ToolError. Errors already carrying a code
+// keep it; others are classified from their type, and anything unrecognised
+// is ErrUnknown.
+func ClassifyError(err error) *ToolError {
+	if err == nil {
+		return nil
+	}
+	var te *ToolError
+	if errors.As(err, &te) {
+		return te
+	}
+	var nav *NavigationError
+	if errors.As(err, &nav) {
+		details := map[string]interface{}{"class": nav.Class, "url": nav.URL}
+		if nav.Status != 0 {
+			details["status"] = nav.Status
+		}
+		return NewToolError(ErrNavigationFailed, err, details)
+	}
+	if errors.Is(err, playwright.ErrTimeout) {
+		return NewToolError(ErrTimeout, err, nil)
+	}
+	return NewToolError(ErrUnknown, err, nil)
+}
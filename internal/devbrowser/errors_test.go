@@ -0,0 +1,54 @@
+package devbrowser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want ErrorCode
+	}{
+		{fmt.Errorf("%w: locator.click: Timeout 5000ms exceeded.", playwright.ErrTimeout), ErrTimeout},
+		{NewToolError(ErrRefStale, errors.New("ref 'e3' is stale"), nil), ErrRefStale},
+		{&NavigationError{Class: "dns", URL: "https://nope.invalid", Err: errors.New("net::ERR_NAME_NOT_RESOLVED")}, ErrNavigationFailed},
+		{invalidArgf("expected non-empty string '%s'", "url"), ErrInvalidArgument},
+		{fmt.Errorf("wrapped: %w", NewToolError(ErrNotVisible, errors.New("hidden"), nil)), ErrNotVisible},
+		{errors.New("something else"), ErrUnknown},
+		// Messages alone are not classified; codes come from the source.
+		{errors.New(`Ref "e3" is stale (element detached). Call snapshot again.`), ErrUnknown},
+		{errors.New("custom timeout handling failed"), ErrUnknown},
+	}
+	for _, tc := range cases {
+		if got := ClassifyError(tc.err); got.Code != tc.want {
+			t.Errorf("%v: got %s, want %s", tc.err, got.Code, tc.want)
+		}
+	}
+
+	missing := ClassifyError(errTargetNotFound(TargetSpec{Selector: "#save"}, fmt.Errorf("%w: waiting for locator", playwright.ErrTimeout)))
+	if missing.Code != ErrNotFound || missing.Details["target"] != `selector="#save" nth=1` {
+		t.Errorf("missing target = %s %v, want not_found with the target", missing.Code, missing.Details)
+	}
+
+	nav := ClassifyError(&NavigationError{Class: "http_4xx", URL: "https://app.test/x", Status: 404, Err: errors.New("404")})
+	if nav.Details["class"] != "http_4xx" || nav.Details["status"] != 404 {
+		t.Errorf("navigation details missing: %v", nav.Details)
+	}
+}
+
+func TestExitCodesDistinct(t *testing.T) {
+	seen := map[int]ErrorCode{}
+	for code, exit := range exitCodes {
+		if other, ok := seen[exit]; ok {
+			t.Fatalf("%s and %s share exit code %d", code, other, exit)
+		}
+		if exit == 0 {
+			t.Fatalf("%s maps to exit code 0", code)
+		}
+		seen[exit] = code
+	}
+}
@@ -0,0 +1,322 @@
+package devbrowser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+var expectElementStates = map[string]bool{
+	"visible": true, "hidden": true, "checked": true, "unchecked": true,
+	"enabled": true, "disabled": true, "expanded": true, "collapsed": true,
+	"editable": true, "focused": true,
+}
+
+// elementStatesJS samples the states the expect tool can assert on.
+const elementStatesJS = `(el) => {
+  const r = el.getBoundingClientRect();
+  const style = getComputedStyle(el);
+  const aria = (name) => el.getAttribute(name);
+  const disabled = el.matches(":disabled") || aria("aria-disabled") === "true";
+  return {
+    visible: r.width > 0 && r.height > 0 && style.visibility !== "hidden" && style.display !== "none",
+    checked: el.checked === true || aria("aria-checked") === "true" || aria("aria-pressed") === "true",
+    disabled,
+    expanded: aria("aria-expanded") === "true" || (el.tagName === "DETAILS" && el.open),
+    editable: !disabled && (el.isContentEditable || (el.matches("input, textarea, select") && !el.readOnly)),
+    focused: el === document.activeElement || el.contains(document.activeElement),
+  };
+}`
+
+// ExpectCheck is one assertion of the expect tool.
+type ExpectCheck struct {
+	Kind     string
+	Target   TargetSpec
+	State    string
+	Count    int
+	Text     string
+	URL      *regexp.Regexp
+	Since    int64
+	Expected interface{}
+}
+
+// ExpectOutcome is a check's last sample.
+type ExpectOutcome struct {
+	Check    string      `json:"check"`
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+	OK       bool        `json:"ok"`
+}
+
+// parseExpectChecks reads the expect tool arguments. Several checks may be
+// combined; all of them must pass.
+func parseExpectChecks(args map[string]interface{}) ([]ExpectCheck, int, error) {
+	spec, err := optionalTargetSpec(args, 5_000)
+	if err != nil {
+		return nil, 0, err
+	}
+	checks := []ExpectCheck{}
+
+	urlPattern, err := optionalString(args, "url_matches", "")
+	if err != nil {
+		return nil, 0, err
+	}
+	if urlPattern != "" {
+		re, err := regexp.Compile(urlPattern)
+		if err != nil {
+			return nil, 0, invalidArgf("invalid url_matches: %v", err)
+		}
+		checks = append(checks, ExpectCheck{Kind: "url_matches", URL: re, Expected: urlPattern})
+	}
+	title, err := optionalString(args, "title_contains", "")
+	if err != nil {
+		return nil, 0, err
+	}
+	if title != "" {
+		checks = append(checks, ExpectCheck{Kind: "title_contains", Text: title, Expected: title})
+	}
+	text, err := optionalString(args, "text_visible", "")
+	if err != nil {
+		return nil, 0, err
+	}
+	if text != "" {
+		checks = append(checks, ExpectCheck{Kind: "text_visible", Text: text, Expected: text})
+	}
+
+	if raw, ok := args["count"]; ok && raw != nil {
+		count, ok := asInt(raw)
+		if !ok || count < 0 {
+			return nil, 0, invalidArgf("count must be a non-negative integer")
+		}
+		if strings.TrimSpace(spec.Ref) != "" || !spec.isSet() {
+			return nil, 0, invalidArgf("count requires a target other than ref")
+		}
+		checks = append(checks, ExpectCheck{Kind: "count", Target: spec, Count: count, Expected: count})
+	} else if spec.isSet() {
+		state, err := optionalString(args, "state", "visible")
+		if err != nil {
+			return nil, 0, err
+		}
+		state = strings.ToLower(state)
+		if !expectElementStates[state] {
+			return nil, 0, invalidArgf("invalid state '%s' (expected one of: visible, hidden, checked, unchecked, enabled, disabled, expanded, collapsed, editable, focused)", state)
+		}
+		checks = append(checks, ExpectCheck{Kind: "state", Target: spec, State: state, Expected: state})
+	}
+
+	noConsoleErrors, err := optionalBool(args, "no_console_errors", false)
+	if err != nil {
+		return nil, 0, err
+	}
+	if noConsoleErrors {
+		since, err := optionalInt(args, "since", 0)
+		if err != nil {
+			return nil, 0, err
+		}
+		if since < 0 {
+			return nil, 0, invalidArgf("since must be >= 0")
+		}
+		checks = append(checks, ExpectCheck{Kind: "no_console_errors", Since: int64(since), Expected: []ConsoleEntry{}})
+	}
+
+	if len(checks) == 0 {
+		return nil, 0, invalidArgf("expect needs a check: url_matches, title_contains, text_visible, a target with state or count, or no_console_errors")
+	}
+	return checks, spec.timeoutMs(), nil
+}
+
+// elementStateMatches reports whether sampled element states satisfy state.
+// A nil sample means the element does not exist.
+func elementStateMatches(state string, states map[string]interface{}) bool {
+	if states == nil {
+		return state == "hidden"
+	}
+	is := func(key string) bool {
+		b, _ := states[key].(bool)
+		return b
+	}
+	switch state {
+	case "hidden":
+		return !is("visible")
+	case "unchecked":
+		return !is("checked")
+	case "enabled":
+		return !is("disabled")
+	case "collapsed":
+		return !is("expanded")
+	}
+	return is(state)
+}
+
+// runExpect polls checks until all pass or timeoutMs runs out. Failures are
+// returned as assertion_failed with each check's expected and actual value.
+func runExpect(page playwright.Page, checks []ExpectCheck, timeoutMs int, events PageEvents) (RunResult, error) {
+	start := time.Now()
+	deadline := start.Add(time.Duration(timeoutMs) * time.Millisecond)
+	for {
+		outcomes := []ExpectOutcome{}
+		failed := []ExpectOutcome{}
+		for _, check := range checks {
+			outcome, err := sampleExpectCheck(page, check, events)
+			if err != nil {
+				// Tool errors (unknown ref, bad args) will not go away;
+				// anything else, such as a navigation destroying the
+				// execution context, is retried until the deadline.
+				var te *ToolError
+				if errors.As(err, &te) {
+					return nil, err
+				}
+				outcome.OK, outcome.Actual = false, "error: "+err.Error()
+			}
+			outcomes = append(outcomes, outcome)
+			if !outcome.OK {
+				failed = append(failed, outcome)
+			}
+		}
+		waited := int(time.Since(start).Milliseconds())
+		if len(failed) == 0 {
+			return RunResult{"ok": true, "checks": outcomes, "waited_ms": waited}, nil
+		}
+		if !time.Now().Before(deadline) {
+			parts := []string{}
+			for _, f := range failed {
+				parts = append(parts, fmt.Sprintf("%s: expected %v, got %v", f.Check, formatExpectValue(f.Expected), formatExpectValue(f.Actual)))
+			}
+			return nil, &ToolError{
+				Code:    ErrAssertionFailed,
+				Message: fmt.Sprintf("expect failed after %dms: %s", waited, strings.Join(parts, "; ")),
+				Details: map[string]interface{}{"checks": outcomes, "failed": failed, "waited_ms": waited},
+			}
+		}
+		page.WaitForTimeout(100)
+	}
+}
+
+func sampleExpectCheck(page playwright.Page, check ExpectCheck, events PageEvents) (ExpectOutcome, error) {
+	out := ExpectOutcome{Check: check.Kind, Expected: check.Expected}
+	switch check.Kind {
+	case "url_matches":
+		url := page.URL()
+		out.Actual, out.OK = url, check.URL.MatchString(url)
+	case "title_contains":
+		title := safeTitle(page)
+		out.Actual, out.OK = title, strings.Contains(title, check.Text)
+	case "text_visible":
+		// The visible=true filter keeps only visible matches,
+		// so a hidden first match does not hide a visible later one.
+		count, err := page.GetByText(check.Text).Locator("visible=true").Count()
+		if err != nil {
+			return out, err
+		}
+		visible := count > 0
+		out.Actual, out.OK = "not visible", visible
+		if visible {
+			out.Actual = "visible"
+		}
+	case "count":
+		spec := check.Target
+		spec.Nth = 1
+		locator, release, err := specMatches(page, spec)
+		if err != nil {
+			return out, err
+		}
+		count, err := locator.Count()
+		release()
+		if err != nil {
+			return out, err
+		}
+		out.Check = "count " + spec.describe()
+		out.Actual, out.OK = count, count == check.Count
+	case "state":
+		states, err := sampleElementStates(page, check.Target)
+		if err != nil {
+			return out, err
+		}
+		out.Check = "state " + check.Target.describe()
+		out.OK = elementStateMatches(check.State, states)
+		if states == nil {
+			out.Actual = "missing"
+		} else {
+			out.Actual = states
+		}
+	case "no_console_errors":
+		if events == nil {
+			return out, NewToolError(ErrDaemonUnavailable, errors.New("no_console_errors requires the daemon's console log"), nil)
+		}
+		logs, _, err := events.ConsoleSince(check.Since, "error", 10)
+		if err != nil {
+			return out, err
+		}
+		out.Actual, out.OK = logs, len(logs) == 0
+	}
+	return out, nil
+}
+
+// sampleElementStates returns nil when the target does not exist (or a ref's
+// element was detached), which only the hidden state accepts. An unknown ref
+// is an error.
+func sampleElementStates(page playwright.Page, spec TargetSpec) (map[string]interface{}, error) {
+	var el playwright.ElementHandle
+	if strings.TrimSpace(spec.Ref) != "" {
+		handle, err := SelectRef(page, spec.Ref, "simple")
+		if err != nil {
+			if staleRefSatisfies("hidden", err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		el = handle
+	} else {
+		locator, release, err := specLocator(page, spec)
+		if err != nil {
+			return nil, err
+		}
+		handles, err := locator.ElementHandles()
+		release()
+		if err != nil {
+			return nil, err
+		}
+		if len(handles) == 0 {
+			return nil, nil
+		}
+		for _, h := range handles[1:] {
+			_ = h.Dispose()
+		}
+		el = handles[0]
+	}
+	defer el.Dispose()
+	raw, err := el.Evaluate(elementStatesJS)
+	if err != nil {
+		return nil, err
+	}
+	states, _ := raw.(map[string]interface{})
+	return states, nil
+}
+
+func formatExpectValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", val)
+	case []ConsoleEntry:
+		if len(val) == 0 {
+			return "no console errors"
+		}
+		return fmt.Sprintf("%d console error(s), first: %q", len(val), val[0].Text)
+	case map[string]interface{}:
+		on := []string{}
+		for _, key := range []string{"visible", "checked", "disabled", "expanded", "editable", "focused"} {
+			if b, _ := val[key].(bool); b {
+				on = append(on, key)
+			}
+		}
+		if len(on) == 0 {
+			return "{}"
+		}
+		return "{" + strings.Join(on, ", ") + "}"
+	}
+	return fmt.Sprint(v)
+}
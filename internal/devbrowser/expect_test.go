@@ -0,0 +1,74 @@
+package devbrowser
+
+import "testing"
+
+func TestParseExpectChecks(t *testing.T) {
+	checks, timeout, err := parseExpectChecks(map[string]interface{}{
+		"url_matches":       `/orders/\d+`,
+		"title_contains":    "Order",
+		"no_console_errors": true,
+		"since":             float64(42),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeout != 5_000 {
+		t.Fatalf("expected default timeout 5000, got %d", timeout)
+	}
+	kinds := []string{}
+	for _, c := range checks {
+		kinds = append(kinds, c.Kind)
+	}
+	if len(kinds) != 3 || kinds[0] != "url_matches" || kinds[1] != "title_contains" || kinds[2] != "no_console_errors" {
+		t.Fatalf("unexpected checks %v", kinds)
+	}
+	if checks[2].Since != 42 {
+		t.Fatalf("expected since 42, got %d", checks[2].Since)
+	}
+
+	checks, _, err = parseExpectChecks(map[string]interface{}{"ref": "e5", "state": "Checked"})
+	if err != nil || len(checks) != 1 || checks[0].Kind != "state" || checks[0].State != "checked" {
+		t.Fatalf("unexpected state check %+v, %v", checks, err)
+	}
+
+	checks, _, err = parseExpectChecks(map[string]interface{}{"selector": "li.row", "count": float64(3)})
+	if err != nil || len(checks) != 1 || checks[0].Kind != "count" || checks[0].Count != 3 {
+		t.Fatalf("unexpected count check %+v, %v", checks, err)
+	}
+
+	bad := []map[string]interface{}{
+		{},
+		{"ref": "e5", "count": float64(2)},
+		{"selector": "li", "count": float64(-1)},
+		{"ref": "e5", "state": "shiny"},
+		{"url_matches": "("},
+	}
+	for _, args := range bad {
+		if _, _, err := parseExpectChecks(args); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestElementStateMatches(t *testing.T) {
+	states := map[string]interface{}{"visible": true, "checked": false, "disabled": true, "expanded": false}
+	cases := map[string]bool{
+		"visible":   true,
+		"hidden":    false,
+		"checked":   false,
+		"unchecked": true,
+		"disabled":  true,
+		"enabled":   false,
+		"expanded":  false,
+		"collapsed": true,
+		"focused":   false,
+	}
+	for state, want := range cases {
+		if got := elementStateMatches(state, states); got != want {
+			t.Fatalf("%s: got %v, want %v", state, got, want)
+		}
+	}
+	if !elementStateMatches("hidden", nil) || elementStateMatches("visible", nil) {
+		t.Fatal("missing element should only satisfy hidden")
+	}
+}
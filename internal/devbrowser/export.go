@@ -0,0 +1,554 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// exportLocator is how a generated test finds an element.
+type exportLocator struct {
+	Kind   string // role, test_id, label, placeholder, text, css
+	Value  string
+	Name   string
+	Exact  bool
+	Nth    int
+	First  bool
+	Within *exportLocator
+}
+
+// locatorFromHints picks the most robust locator among the hints captured
+// for a ref: role and name, then test id, label, placeholder and CSS.
+func locatorFromHints(hints map[string]interface{}) (exportLocator, bool) {
+	str := func(key string) string {
+		s, _ := hints[key].(string)
+		return strings.TrimSpace(s)
+	}
+	nth, _ := asInt(hints["nth"])
+	switch {
+	case str("role") != "" && str("name") != "":
+		return exportLocator{Kind: "role", Value: str("role"), Name: str("name"), Exact: true, Nth: nth}, true
+	case str("test_id") != "":
+		return exportLocator{Kind: "test_id", Value: str("test_id")}, true
+	case str("label") != "":
+		return exportLocator{Kind: "label", Value: str("label"), Exact: true}, true
+	case str("placeholder") != "":
+		return exportLocator{Kind: "placeholder", Value: str("placeholder"), Exact: true}, true
+	case str("css") != "":
+		return exportLocator{Kind: "css", Value: str("css")}, true
+	}
+	return exportLocator{}, false
+}
+
+// entryLocator is the locator for a logged call: captured ref hints, or the
+// call's own target arguments.
+func entryLocator(entry SessionEntry) (exportLocator, bool) {
+	if len(entry.Target) > 0 {
+		return locatorFromHints(entry.Target)
+	}
+	var reserved []string
+	if entry.Tool == "fill_ref" || entry.Tool == "wait" {
+		reserved = []string{"text"}
+	}
+	spec, err := optionalTargetSpec(entry.Args, 5_000, reserved...)
+	if err != nil || !spec.isSet() {
+		return exportLocator{}, false
+	}
+	return specExportLocator(spec)
+}
+
+// specExportLocator converts a target to a locator. Refs have no stable
+// form, so a target under a ref parent cannot be exported.
+func specExportLocator(spec TargetSpec) (exportLocator, bool) {
+	loc := exportLocator{Exact: spec.Exact, Nth: spec.Nth}
+	switch {
+	case strings.TrimSpace(spec.Ref) != "":
+		return loc, false
+	case strings.TrimSpace(spec.Selector) != "":
+		loc.Kind, loc.Value = "css", spec.Selector
+	case strings.TrimSpace(spec.XPath) != "":
+		loc.Kind, loc.Value = "css", "xpath="+spec.XPath
+	case strings.TrimSpace(spec.Text) != "":
+		loc.Kind, loc.Value = "text", spec.Text
+	case strings.TrimSpace(spec.Label) != "":
+		loc.Kind, loc.Value = "label", spec.Label
+	case strings.TrimSpace(spec.Placeholder) != "":
+		loc.Kind, loc.Value = "placeholder", spec.Placeholder
+	case strings.TrimSpace(spec.TestID) != "":
+		loc.Kind, loc.Value = "test_id", spec.TestID
+	default:
+		loc.Kind, loc.Value, loc.Name = "role", spec.AriaRole, spec.AriaName
+	}
+	if spec.Within != nil {
+		parent, ok := specExportLocator(*spec.Within)
+		if !ok {
+			return loc, false
+		}
+		loc.Within = &parent
+	}
+	return loc, true
+}
+
+// exporter renders logged calls as one Playwright test in lang (ts or go).
+type exporter struct {
+	lang       string
+	lines      []string
+	usesRegexp bool
+	usesExpect bool
+	usesEnv    bool
+	skipped    int
+}
+
+// ExportPlaywright turns a session log into a Playwright test. Navigations
+// caused by clicks, fills and key presses become explicit URL waits so the
+// test does not race the page.
+func ExportPlaywright(entries []SessionEntry, lang, name string) (string, int, error) {
+	if lang != "ts" && lang != "go" {
+		return "", 0, invalidArgf("invalid lang '%s' (expected ts or go)", lang)
+	}
+	if strings.TrimSpace(name) == "" {
+		name = "recorded session"
+	}
+	e := &exporter{lang: lang}
+	for _, entry := range entries {
+		e.entry(entry)
+	}
+	if lang == "ts" {
+		return e.typescript(name), e.skipped, nil
+	}
+	src, err := e.golang(name)
+	return src, e.skipped, err
+}
+
+func (e *exporter) entry(entry SessionEntry) {
+	args := entry.Args
+	str := func(key string) string {
+		s, _ := args[key].(string)
+		return s
+	}
+	switch entry.Tool {
+	case "goto":
+		waitUntil := str("wait_until")
+		if waitUntil == "" {
+			waitUntil = "domcontentloaded"
+		}
+		e.gotoURL(str("url"), waitUntil)
+		return
+	case "back", "forward", "reload":
+		e.history(entry.Tool)
+		return
+	case "click_ref":
+		if loc, ok := entryLocator(entry); ok {
+			e.action(loc, "click", "")
+		} else {
+			e.skip(entry, "ref could not be described")
+		}
+	case "fill_ref":
+		if loc, ok := entryLocator(entry); ok {
+			e.action(loc, "fill", str("text"))
+		} else {
+			e.skip(entry, "ref could not be described")
+		}
+	case "press":
+		e.press(str("key"))
+	case "click_xy":
+		if loc, ok := entryLocator(entry); ok {
+			e.action(loc, "click", "")
+		} else {
+			x, _ := args["x"].(float64)
+			y, _ := args["y"].(float64)
+			e.mouse("click", x, y)
+		}
+	case "mouse_wheel":
+		dx, _ := args["delta_x"].(float64)
+		dy, _ := args["delta_y"].(float64)
+		e.mouse("wheel", dx, dy)
+	case "wait":
+		e.wait(entry)
+		return
+	case "expect":
+		e.expect(entry)
+		return
+	default:
+		e.skip(entry, "no Playwright equivalent")
+	}
+	if entry.URLAfter != "" && entry.URLAfter != entry.URLBefore {
+		e.waitForURL(entry.URLAfter)
+	}
+}
+
+func (e *exporter) skip(entry SessionEntry, reason string) {
+	e.skipped++
+	args, _ := json.Marshal(entry.Args)
+	e.comment(fmt.Sprintf("TODO: %s %s (%s)", entry.Tool, args, reason))
+}
+
+func (e *exporter) comment(text string) {
+	e.lines = append(e.lines, "// "+strings.ReplaceAll(text, "\n", " "))
+}
+
+func (e *exporter) quote(s string) string {
+	if e.lang == "go" {
+		return strconv.Quote(s)
+	}
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSpace(b.String())
+}
+
+func (e *exporter) regexp(pattern string) string {
+	if e.lang == "go" {
+		e.usesRegexp = true
+		return "regexp.MustCompile(" + e.quote(pattern) + ")"
+	}
+	return "new RegExp(" + e.quote(pattern) + ")"
+}
+
+// call renders a statement that returns only an error in Go.
+func (e *exporter) call(ts, goExpr string) {
+	if e.lang == "ts" {
+		e.lines = append(e.lines, "await "+ts+";")
+		return
+	}
+	e.lines = append(e.lines, "if err := "+goExpr+"; err != nil {", "t.Fatal(err)", "}")
+}
+
+// call2 renders a statement that returns a value and an error in Go.
+func (e *exporter) call2(ts, goExpr string) {
+	if e.lang == "ts" {
+		e.lines = append(e.lines, "await "+ts+";")
+		return
+	}
+	e.lines = append(e.lines, "if _, err := "+goExpr+"; err != nil {", "t.Fatal(err)", "}")
+}
+
+func (e *exporter) locator(loc exportLocator) string {
+	base, opts := "page", "Page"
+	if loc.Within != nil {
+		base, opts = e.locator(*loc.Within), "Locator"
+	}
+	var out string
+	if e.lang == "ts" {
+		exact := ""
+		if loc.Exact {
+			exact = ", { exact: true }"
+		}
+		switch loc.Kind {
+		case "role":
+			out = base + ".getByRole(" + e.quote(loc.Value)
+			if loc.Name != "" {
+				out += ", { name: " + e.quote(loc.Name)
+				if loc.Exact {
+					out += ", exact: true"
+				}
+				out += " }"
+			}
+			out += ")"
+		case "test_id":
+			out = base + ".getByTestId(" + e.quote(loc.Value) + ")"
+		case "label":
+			out = base + ".getByLabel(" + e.quote(loc.Value) + exact + ")"
+		case "placeholder":
+			out = base + ".getByPlaceholder(" + e.quote(loc.Value) + exact + ")"
+		case "text":
+			out = base + ".getByText(" + e.quote(loc.Value) + exact + ")"
+		default:
+			out = base + ".locator(" + e.quote(loc.Value) + ")"
+		}
+		if loc.First {
+			out += ".first()"
+		} else if loc.Nth > 1 {
+			out += fmt.Sprintf(".nth(%d)", loc.Nth-1)
+		}
+		return out
+	}
+	exact := ""
+	if loc.Exact {
+		exact = "Exact: playwright.Bool(true)"
+	}
+	options := func(kind string) string {
+		if exact == "" {
+			return ""
+		}
+		return ", playwright." + opts + "GetBy" + kind + "Options{" + exact + "}"
+	}
+	switch loc.Kind {
+	case "role":
+		out = base + ".GetByRole(" + e.quote(loc.Value)
+		if loc.Name != "" {
+			fields := "Name: " + e.quote(loc.Name)
+			if exact != "" {
+				fields += ", " + exact
+			}
+			out += ", playwright." + opts + "GetByRoleOptions{" + fields + "}"
+		}
+		out += ")"
+	case "test_id":
+		out = base + ".GetByTestId(" + e.quote(loc.Value) + ")"
+	case "label":
+		out = base + ".GetByLabel(" + e.quote(loc.Value) + options("Label") + ")"
+	case "placeholder":
+		out = base + ".GetByPlaceholder(" + e.quote(loc.Value) + options("Placeholder") + ")"
+	case "text":
+		out = base + ".GetByText(" + e.quote(loc.Value) + options("Text") + ")"
+	default:
+		out = base + ".Locator(" + e.quote(loc.Value) + ")"
+	}
+	if loc.First {
+		out += ".First()"
+	} else if loc.Nth > 1 {
+		out += fmt.Sprintf(".Nth(%d)", loc.Nth-1)
+	}
+	return out
+}
+
+func (e *exporter) gotoURL(url, waitUntil string) {
+	goState := map[string]string{
+		"load": "WaitUntilStateLoad", "domcontentloaded": "WaitUntilStateDomcontentloaded",
+		"networkidle": "WaitUntilStateNetworkidle", "commit": "WaitUntilStateCommit",
+	}[waitUntil]
+	if goState == "" {
+		goState = "WaitUntilStateDomcontentloaded"
+		waitUntil = "domcontentloaded"
+	}
+	e.call2(
+		"page.goto("+e.quote(url)+", { waitUntil: "+e.quote(waitUntil)+" })",
+		"page.Goto("+e.quote(url)+", playwright.PageGotoOptions{WaitUntil: playwright."+goState+"})",
+	)
+}
+
+func (e *exporter) history(tool string) {
+	ts := map[string]string{"back": "goBack", "forward": "goForward", "reload": "reload"}[tool]
+	goName := map[string]string{"back": "GoBack", "forward": "GoForward", "reload": "Reload"}[tool]
+	e.call2("page."+ts+"()", "page."+goName+"()")
+}
+
+func (e *exporter) action(loc exportLocator, kind, text string) {
+	l := e.locator(loc)
+	if kind == "fill" {
+		value := e.fillValue(text)
+		e.call(l+".fill("+value[0]+")", l+".Fill("+value[1]+")")
+		return
+	}
+	e.call(l+".click()", l+".Click()")
+}
+
+var varPlaceholder = regexp.MustCompile(`^\$\{vars\.([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// fillValue renders a fill's text for ts and go. Values redacted in the
+// session log (${vars.password}) are read from the environment instead.
+func (e *exporter) fillValue(text string) [2]string {
+	m := varPlaceholder.FindStringSubmatch(text)
+	if m == nil {
+		return [2]string{e.quote(text), e.quote(text)}
+	}
+	e.usesEnv = true
+	env := e.quote(strings.ToUpper(m[1]))
+	return [2]string{"process.env[" + env + "] ?? \"\"", "os.Getenv(" + env + ")"}
+}
+
+func (e *exporter) press(key string) {
+	e.call("page.keyboard.press("+e.quote(key)+")", "page.Keyboard().Press("+e.quote(key)+")")
+}
+
+func (e *exporter) mouse(kind string, a, b float64) {
+	pa := strconv.FormatFloat(a, 'f', -1, 64)
+	pb := strconv.FormatFloat(b, 'f', -1, 64)
+	if kind == "wheel" {
+		e.call("page.mouse.wheel("+pa+", "+pb+")", "page.Mouse().Wheel("+pa+", "+pb+")")
+		return
+	}
+	e.call("page.mouse.click("+pa+", "+pb+")", "page.Mouse().Click("+pa+", "+pb+")")
+}
+
+func (e *exporter) waitForURL(url string) {
+	e.call("page.waitForURL("+e.quote(url)+")", "page.WaitForURL("+e.quote(url)+")")
+}
+
+func (e *exporter) waitForLoadState(state string) {
+	goState := map[string]string{"load": "LoadStateLoad", "domcontentloaded": "LoadStateDomcontentloaded", "networkidle": "LoadStateNetworkidle"}[state]
+	e.call(
+		"page.waitForLoadState("+e.quote(state)+")",
+		"page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{State: playwright."+goState+"})",
+	)
+}
+
+func (e *exporter) wait(entry SessionEntry) {
+	cond, err := parseWaitCondition(entry.Args)
+	if err != nil {
+		e.skip(entry, err.Error())
+		return
+	}
+	if cond == nil {
+		strategy, _ := entry.Args["strategy"].(string)
+		state, _ := entry.Args["state"].(string)
+		switch {
+		case strategy == "perf" || strategy == "network":
+			e.waitForLoadState("networkidle")
+		case strategy == "stable":
+			e.skip(entry, "no Playwright equivalent")
+		case state == "domcontentloaded" || state == "networkidle":
+			e.waitForLoadState(state)
+		default:
+			e.waitForLoadState("load")
+		}
+		return
+	}
+	switch cond.Kind {
+	case "element":
+		loc, ok := entryLocator(entry)
+		if !ok {
+			e.skip(entry, "target could not be described")
+			return
+		}
+		switch cond.State {
+		case "visible", "hidden", "attached", "detached":
+			goState := "WaitForSelectorState" + strings.ToUpper(cond.State[:1]) + cond.State[1:]
+			l := e.locator(loc)
+			e.call(
+				l+".waitFor({ state: "+e.quote(cond.State)+" })",
+				l+".WaitFor(playwright.LocatorWaitForOptions{State: playwright."+goState+"})",
+			)
+		default:
+			e.assertState(loc, cond.State)
+		}
+	case "text":
+		e.assertState(exportLocator{Kind: "text", Value: cond.Text, First: true}, "visible")
+	case "url":
+		re := e.regexp(cond.URL.String())
+		e.call("page.waitForURL("+re+")", "page.WaitForURL("+re+")")
+	case "function":
+		e.call2("page.waitForFunction("+e.quote(cond.Function)+")", "page.WaitForFunction("+e.quote(cond.Function)+", nil)")
+	default:
+		e.skip(entry, "no Playwright equivalent")
+	}
+}
+
+func (e *exporter) expect(entry SessionEntry) {
+	checks, _, err := parseExpectChecks(entry.Args)
+	if err != nil {
+		e.skip(entry, err.Error())
+		return
+	}
+	for _, check := range checks {
+		switch check.Kind {
+		case "url_matches":
+			e.assertPage("toHaveURL", "ToHaveURL", e.regexp(check.URL.String()))
+		case "title_contains":
+			e.assertPage("toHaveTitle", "ToHaveTitle", e.regexp(regexp.QuoteMeta(check.Text)))
+		case "text_visible":
+			e.assertState(exportLocator{Kind: "text", Value: check.Text, First: true}, "visible")
+		case "count":
+			loc, ok := entryLocator(entry)
+			if !ok {
+				e.skip(entry, "target could not be described")
+				continue
+			}
+			loc.Nth = 0
+			e.usesExpect = true
+			l := e.locator(loc)
+			e.call(fmt.Sprintf("expect(%s).toHaveCount(%d)", l, check.Count), fmt.Sprintf("expect.Locator(%s).ToHaveCount(%d)", l, check.Count))
+		case "state":
+			loc, ok := entryLocator(entry)
+			if !ok {
+				e.skip(entry, "target could not be described")
+				continue
+			}
+			e.assertState(loc, check.State)
+		default:
+			e.skipped++
+			e.comment("TODO: " + check.Kind + " is checked by dev-browser only")
+		}
+	}
+}
+
+func (e *exporter) assertPage(ts, goName, arg string) {
+	e.usesExpect = true
+	e.call("expect(page)."+ts+"("+arg+")", "expect.Page(page)."+goName+"("+arg+")")
+}
+
+// assertState renders an element state as a web-first assertion.
+func (e *exporter) assertState(loc exportLocator, state string) {
+	e.usesExpect = true
+	matchers := map[string][2]string{
+		"visible":   {"toBeVisible()", "ToBeVisible()"},
+		"hidden":    {"toBeHidden()", "ToBeHidden()"},
+		"attached":  {"toBeAttached()", "ToBeAttached()"},
+		"detached":  {"not.toBeAttached()", "Not().ToBeAttached()"},
+		"checked":   {"toBeChecked()", "ToBeChecked()"},
+		"unchecked": {"not.toBeChecked()", "Not().ToBeChecked()"},
+		"enabled":   {"toBeEnabled()", "ToBeEnabled()"},
+		"disabled":  {"toBeDisabled()", "ToBeDisabled()"},
+		"editable":  {"toBeEditable()", "ToBeEditable()"},
+		"focused":   {"toBeFocused()", "ToBeFocused()"},
+		"expanded":  {`toHaveAttribute("aria-expanded", "true")`, `ToHaveAttribute("aria-expanded", "true")`},
+		"collapsed": {`not.toHaveAttribute("aria-expanded", "true")`, `Not().ToHaveAttribute("aria-expanded", "true")`},
+	}
+	m, ok := matchers[state]
+	if !ok {
+		m = matchers["visible"]
+	}
+	l := e.locator(loc)
+	e.call("expect("+l+")."+m[0], "expect.Locator("+l+")."+m[1])
+}
+
+func (e *exporter) typescript(name string) string {
+	var b strings.Builder
+	b.WriteString("import { test, expect } from \"@playwright/test\";\n\n")
+	b.WriteString("test(" + e.quote(name) + ", async ({ page }) => {\n")
+	for _, line := range e.lines {
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("});\n")
+	return b.String()
+}
+
+func (e *exporter) golang(name string) (string, error) {
+	var b strings.Builder
+	b.WriteString("package e2e\n\nimport (\n")
+	if e.usesEnv {
+		b.WriteString("\"os\"\n")
+	}
+	if e.usesRegexp {
+		b.WriteString("\"regexp\"\n")
+	}
+	b.WriteString("\"testing\"\n\n\"github.com/playwright-community/playwright-go\"\n)\n\n")
+	b.WriteString("func " + goTestName(name) + "(t *testing.T) {\n")
+	b.WriteString("pw, err := playwright.Run()\nif err != nil {\nt.Fatal(err)\n}\ndefer pw.Stop()\n")
+	b.WriteString("browser, err := pw.Chromium.Launch()\nif err != nil {\nt.Fatal(err)\n}\ndefer browser.Close()\n")
+	b.WriteString("page, err := browser.NewPage()\nif err != nil {\nt.Fatal(err)\n}\n")
+	if e.usesExpect {
+		b.WriteString("expect := playwright.NewPlaywrightAssertions()\n")
+	}
+	b.WriteString("\n")
+	for _, line := range e.lines {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("}\n")
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("format generated test: %w", err)
+	}
+	return string(src), nil
+}
+
+var nonWordPattern = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// goTestName turns "checkout flow" into TestCheckoutFlow.
+func goTestName(name string) string {
+	out := "Test"
+	for _, word := range nonWordPattern.Split(name, -1) {
+		if word != "" {
+			out += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	if out == "Test" {
+		out = "TestSession"
+	}
+	return out
+}
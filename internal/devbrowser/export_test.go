@@ -0,0 +1,185 @@
+package devbrowser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func exportFixture() []SessionEntry {
+	return []SessionEntry{
+		{Tool: "goto", Args: map[string]interface{}{"url": "https://example.com/login"}, URLBefore: "about:blank", URLAfter: "https://example.com/login"},
+		{Tool: "fill_ref", Args: map[string]interface{}{"ref": "e3", "text": "a@b.c"},
+			Target:    map[string]interface{}{"role": "textbox", "name": "Email", "nth": float64(1), "label": "Email", "css": "#email"},
+			URLBefore: "https://example.com/login", URLAfter: "https://example.com/login"},
+		{Tool: "click_ref", Args: map[string]interface{}{"ref": "e7"},
+			Target:    map[string]interface{}{"test_id": "submit", "css": "form > button"},
+			URLBefore: "https://example.com/login", URLAfter: "https://example.com/home"},
+		{Tool: "expect", Args: map[string]interface{}{"title_contains": "Home (beta)"}},
+		{Tool: "expect", Args: map[string]interface{}{"aria_role": "button", "aria_name": "Log out", "nth": float64(2), "state": "enabled"}},
+		{Tool: "click_ref", Args: map[string]interface{}{"text": "Delete", "exact": true, "within": map[string]interface{}{"aria_role": "dialog"}}},
+		{Tool: "scroll", Args: map[string]interface{}{"dy": float64(400)}},
+	}
+}
+
+func TestExportPlaywrightTypeScript(t *testing.T) {
+	src, skipped, err := ExportPlaywright(exportFixture(), "ts", "login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`test("login", async ({ page }) => {`,
+		`await page.goto("https://example.com/login", { waitUntil: "domcontentloaded" });`,
+		`await page.getByRole("textbox", { name: "Email", exact: true }).fill("a@b.c");`,
+		`await page.getByTestId("submit").click();`,
+		`await page.waitForURL("https://example.com/home");`,
+		`await expect(page).toHaveTitle(new RegExp("Home \\(beta\\)"));`,
+		`await expect(page.getByRole("button", { name: "Log out" }).nth(1)).toBeEnabled();`,
+		`await page.getByRole("dialog").getByText("Delete", { exact: true }).click();`,
+		`// TODO: scroll`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Fatalf("missing %q in:\n%s", want, src)
+		}
+	}
+	if skipped != 1 {
+		t.Fatalf("skipped = %d, want 1", skipped)
+	}
+}
+
+func TestExportPlaywrightGo(t *testing.T) {
+	src, _, err := ExportPlaywright(exportFixture(), "go", "login flow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"func TestLoginFlow(t *testing.T) {",
+		`"regexp"`,
+		`page.Goto("https://example.com/login", playwright.PageGotoOptions{WaitUntil: playwright.WaitUntilStateDomcontentloaded})`,
+		`page.GetByTestId("submit").Click()`,
+		`page.WaitForURL("https://example.com/home")`,
+		"expect := playwright.NewPlaywrightAssertions()",
+		`page.GetByRole("dialog").GetByText("Delete", playwright.LocatorGetByTextOptions{Exact: playwright.Bool(true)}).Click()`,
+		`expect.Locator(page.GetByRole("button", playwright.PageGetByRoleOptions{Name: "Log out"}).Nth(1)).ToBeEnabled()`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Fatalf("missing %q in:\n%s", want, src)
+		}
+	}
+}
+
+func TestExportPlaywrightRejectsLang(t *testing.T) {
+	if _, _, err := ExportPlaywright(nil, "py", "x"); err == nil {
+		t.Fatal("expected error for unknown lang")
+	}
+}
+
+func TestLocatorFromHintsOrder(t *testing.T) {
+	cases := []struct {
+		hints map[string]interface{}
+		kind  string
+	}{
+		{map[string]interface{}{"role": "button", "name": "Go", "test_id": "go", "css": "#go"}, "role"},
+		{map[string]interface{}{"role": "button", "test_id": "go", "css": "#go"}, "test_id"},
+		{map[string]interface{}{"label": "Email", "placeholder": "you@x", "css": "#e"}, "label"},
+		{map[string]interface{}{"placeholder": "you@x", "css": "#e"}, "placeholder"},
+		{map[string]interface{}{"css": "div > a"}, "css"},
+	}
+	for _, tc := range cases {
+		loc, ok := locatorFromHints(tc.hints)
+		if !ok || loc.Kind != tc.kind {
+			t.Fatalf("locatorFromHints(%v) = %+v, want %s", tc.hints, loc, tc.kind)
+		}
+	}
+	if _, ok := locatorFromHints(map[string]interface{}{}); ok {
+		t.Fatal("expected no locator for empty hints")
+	}
+}
+
+func TestReadSessionLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.jsonl")
+	entries, err := ReadSessionLog(path)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("missing log: %v %v", entries, err)
+	}
+	data := `{"tool":"goto","args":{"url":"https://a"},"url_before":"","url_after":"https://a","time_ms":1}
+not json
+
+{"tool":"press","args":{"key":"Enter"},"url_before":"https://a","url_after":"https://a","time_ms":2}
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = ReadSessionLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Tool != "press" {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestExportReadsRedactedFillsFromEnv(t *testing.T) {
+	entries := []SessionEntry{{
+		Tool:   "fill_ref",
+		Args:   map[string]interface{}{"ref": "e4", "text": "${vars.password}"},
+		Target: map[string]interface{}{"label": "Password"},
+	}}
+	ts, _, err := ExportPlaywright(entries, "ts", "login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `await page.getByLabel("Password", { exact: true }).fill(process.env["PASSWORD"] ?? "");`; !strings.Contains(ts, want) {
+		t.Fatalf("missing %q in:\n%s", want, ts)
+	}
+	src, _, err := ExportPlaywright(entries, "go", "login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"os"`, `Fill(os.Getenv("PASSWORD"))`} {
+		if !strings.Contains(src, want) {
+			t.Fatalf("missing %q in:\n%s", want, src)
+		}
+	}
+}
+
+func TestSessionLogIsOptIn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions", "main.jsonl")
+	resetSessionLog(path)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("reset created a log: %v", err)
+	}
+	if err := StartSessionLog(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"tool":"goto"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resetSessionLog(path)
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) != 0 {
+		t.Fatalf("reset left %q (%v)", data, err)
+	}
+}
+
+func TestRedactFillFormArgs(t *testing.T) {
+	args := map[string]interface{}{"values": map[string]interface{}{"Email": "a@b.c", "Password": "hunter2"}}
+	got := redactArgs(args, "values", formFieldNames(args["values"]))
+	values := got["values"].(map[string]interface{})
+	if values["Password"] != "${vars.Password}" || values["Email"] != "${vars.Email}" {
+		t.Fatalf("values = %v", values)
+	}
+	if args["values"].(map[string]interface{})["Password"] != "hunter2" {
+		t.Fatal("redactArgs modified the caller's args")
+	}
+}
+
+func TestRedactFillFormListArgs(t *testing.T) {
+	list := []interface{}{map[string]interface{}{"field": "Password", "value": "hunter2"}}
+	got := formFieldNames(list).([]interface{})
+	entry := got[0].(map[string]interface{})
+	if entry["field"] != "Password" || entry["value"] != "${vars.Password}" {
+		t.Fatalf("values = %v", got)
+	}
+}
@@ -0,0 +1,200 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type FillResult struct {
+	Kind     string
+	Strategy string
+}
+
+const fillKindJS = `(el) => {
+  const tag = el.tagName.toLowerCase();
+  const role = (el.getAttribute("role") || "").toLowerCase();
+  if (tag === "input") {
+    const type = (el.type || "text").toLowerCase();
+    const base = { tag, type, role };
+    if (type === "checkbox" || type === "radio") return { ...base, kind: "checkbox" };
+    if (type === "range") return { ...base, kind: "range" };
+    if (type === "color") return { ...base, kind: "color" };
+    if (el.readOnly) return { ...base, kind: "readonly" };
+    if (["date", "time", "month", "week", "datetime-local"].includes(type)) return { ...base, kind: "date" };
+    if (["button", "submit", "reset", "image", "file", "hidden"].includes(type)) return { ...base, kind: "unsupported" };
+    return { ...base, kind: "text" };
+  }
+  if (tag === "textarea") return { tag, type: "textarea", role, kind: el.readOnly ? "readonly" : "text" };
+  if (tag === "select") return { tag, type: "select", role, kind: "select" };
+  if (el.isContentEditable) return { tag, type: "", role, kind: "contenteditable" };
+  return { tag, type: "", role, kind: "unsupported" };
+}`
+
+// setValueJS uses the native value setter from el's own prototype chain, so
+// frameworks that shadow "value" on the instance (React) see the change.
+const setValueJS = `(el, value) => {
+  let desc = null;
+  for (let proto = Object.getPrototypeOf(el); proto && !desc; proto = Object.getPrototypeOf(proto)) {
+    const d = Object.getOwnPropertyDescriptor(proto, "value");
+    if (d && d.set) desc = d;
+  }
+  if (desc) desc.set.call(el, value);
+  else el.value = value;
+  el.dispatchEvent(new Event("input", { bubbles: true }));
+  el.dispatchEvent(new Event("change", { bubbles: true }));
+  return el.value;
+}`
+
+// uncheckRadioJS clears a radio, which Playwright refuses to do because a
+// user cannot.
+const uncheckRadioJS = `(el) => {
+  if (!el.checked) return;
+  el.checked = false;
+  el.dispatchEvent(new Event("input", { bubbles: true }));
+  el.dispatchEvent(new Event("change", { bubbles: true }));
+}`
+
+const selectEditableJS = `(el) => {
+  el.focus();
+  const sel = el.ownerDocument.getSelection();
+  if (sel) sel.selectAllChildren(el);
+}`
+
+var dateFormats = map[string]string{
+	"date":           "YYYY-MM-DD",
+	"time":           "HH:MM",
+	"month":          "YYYY-MM",
+	"week":           "YYYY-Www",
+	"datetime-local": "YYYY-MM-DDTHH:MM",
+}
+
+// SmartFill fills el with text using a strategy suited to the element kind:
+// "fill" for text inputs, "set_value" (value setter plus input/change events)
+// for range, color and date inputs, whose value read back tells whether the
+// input accepted it, "type" (clear then key typing) for contenteditable
+// editors, "select_option" for selects and
+// "set_checked" for checkboxes. strategy "auto" picks one; any other value
+// forces that strategy.
+func SmartFill(page playwright.Page, el playwright.ElementHandle, text string, strategy string, timeoutMs int) (FillResult, error) {
+	raw, err := el.Evaluate(fillKindJS)
+	if err != nil {
+		return FillResult{}, err
+	}
+	info, _ := raw.(map[string]interface{})
+	kind, _ := info["kind"].(string)
+	inputType, _ := info["type"].(string)
+	tag, _ := info["tag"].(string)
+	res := FillResult{Kind: kind}
+
+	strategy, err = pickFillStrategy(strategy, kind, tag, inputType)
+	if err != nil {
+		return res, err
+	}
+
+	timeout := playwright.Float(float64(timeoutMs))
+	switch strategy {
+	case "fill":
+		if err := el.Fill(text, playwright.ElementHandleFillOptions{Timeout: timeout}); err != nil {
+			return res, err
+		}
+	case "set_value":
+		val, err := el.Evaluate(setValueJS, text)
+		if err != nil {
+			return res, err
+		}
+		got, _ := val.(string)
+		// Date inputs sanitize a value they cannot parse to "", but may
+		// normalize one they can (seconds dropped), so only empty is a
+		// rejection.
+		if got == "" && text != "" && kind == "date" {
+			return res, fmt.Errorf("%s input rejected '%s' (expected %s)", inputType, text, dateFormats[inputType])
+		}
+		if got != text && kind == "select" {
+			return res, fmt.Errorf("select has no option with value '%s'", text)
+		}
+	case "type":
+		// Selecting all children only clears an editor; an input or
+		// textarea is emptied with fill so typing replaces its value.
+		if kind == "contenteditable" {
+			if _, err := el.Evaluate(selectEditableJS); err != nil {
+				return res, err
+			}
+			if err := page.Keyboard().Press("Backspace"); err != nil {
+				return res, err
+			}
+		} else if err := el.Fill("", playwright.ElementHandleFillOptions{Timeout: timeout}); err != nil {
+			return res, err
+		}
+		if err := page.Keyboard().Type(text); err != nil {
+			return res, err
+		}
+	case "select_option":
+		values := []string{text}
+		if _, err := el.SelectOption(playwright.SelectOptionValues{Values: &values}, playwright.ElementHandleSelectOptionOptions{Timeout: timeout}); err != nil {
+			return res, err
+		}
+	case "set_checked":
+		checked, err := formValueBool(text)
+		if err != nil {
+			return res, err
+		}
+		if !checked && inputType == "radio" {
+			if _, err := el.Evaluate(uncheckRadioJS); err != nil {
+				return res, err
+			}
+			break
+		}
+		if err := el.SetChecked(checked, playwright.ElementHandleSetCheckedOptions{Timeout: timeout}); err != nil {
+			return res, err
+		}
+	}
+	res.Strategy = strategy
+	return res, nil
+}
+
+// pickFillStrategy resolves "auto" for an element kind and checks that a
+// forced strategy can work on it.
+func pickFillStrategy(strategy, kind, tag, inputType string) (string, error) {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if strategy == "" {
+		strategy = "auto"
+	}
+	switch strategy {
+	case "auto":
+		switch kind {
+		case "text":
+			return "fill", nil
+		case "range", "color", "date":
+			return "set_value", nil
+		case "contenteditable":
+			return "type", nil
+		case "select":
+			return "select_option", nil
+		case "checkbox":
+			return "set_checked", nil
+		case "readonly":
+			return "", fmt.Errorf("element is read-only (%s type=%s); it is likely a custom picker: click it and choose a value, or pass strategy=set_value to force the value", tag, inputType)
+		}
+		return "", fmt.Errorf("element cannot be filled (%s); use click_ref/press or a form control inside it", tag)
+	case "set_value":
+		if kind == "contenteditable" || kind == "unsupported" || kind == "checkbox" {
+			return "", invalidArgf("strategy set_value needs an input, textarea or select (got %s)", describeFillKind(kind, tag, inputType))
+		}
+	case "fill", "type", "select_option", "set_checked":
+	default:
+		return "", invalidArgf("invalid strategy '%s' (expected auto, fill, set_value, type, select_option, set_checked)", strategy)
+	}
+	return strategy, nil
+}
+
+func describeFillKind(kind, tag, inputType string) string {
+	if kind == "contenteditable" {
+		return "contenteditable " + tag
+	}
+	if inputType != "" && inputType != tag {
+		return fmt.Sprintf("%s type=%s", tag, inputType)
+	}
+	return tag
+}
@@ -0,0 +1,85 @@
+package devbrowser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPickFillStrategy(t *testing.T) {
+	cases := []struct {
+		strategy, kind, tag, inputType string
+		want                           string
+	}{
+		{"auto", "text", "input", "email", "fill"},
+		{"", "date", "input", "date", "set_value"},
+		{"auto", "range", "input", "range", "set_value"},
+		{"auto", "contenteditable", "div", "", "type"},
+		{"auto", "select", "select", "select", "select_option"},
+		{"AUTO", "checkbox", "input", "radio", "set_checked"},
+		{"set_value", "readonly", "input", "text", "set_value"},
+		{"set_value", "select", "select", "select", "set_value"},
+		{"type", "text", "input", "text", "type"},
+	}
+	for _, tc := range cases {
+		got, err := pickFillStrategy(tc.strategy, tc.kind, tc.tag, tc.inputType)
+		if err != nil || got != tc.want {
+			t.Errorf("%s on %s: got %q, %v; want %q", tc.strategy, tc.kind, got, err, tc.want)
+		}
+	}
+
+	for _, tc := range []struct{ strategy, kind, tag string }{
+		{"set_value", "contenteditable", "div"},
+		{"set_value", "checkbox", "input"},
+		{"paste", "text", "input"},
+	} {
+		_, err := pickFillStrategy(tc.strategy, tc.kind, tc.tag, "")
+		var te *ToolError
+		if !errors.As(err, &te) || te.Code != ErrInvalidArgument {
+			t.Errorf("%s on %s: expected invalid_argument, got %v", tc.strategy, tc.kind, err)
+		}
+	}
+	if _, err := pickFillStrategy("auto", "readonly", "input", "text"); err == nil {
+		t.Error("expected auto to refuse a read-only input")
+	}
+}
+
+func TestSetValueUsesElementPrototypeSetter(t *testing.T) {
+	res := runSnapshotJS(t, fmt.Sprintf(`
+globalThis.Event = class { constructor(type) { this.type = type; } };
+class Base {
+  get value() { return this._v || ""; }
+  set value(v) { this._v = "base:" + v; }
+}
+class Select extends Base {
+  get value() { return this._v || ""; }
+  set value(v) { this._v = v; this.via = "select"; }
+}
+const el = new Select();
+el.events = [];
+el.dispatchEvent = (e) => el.events.push(e.type);
+const got = (%s)(el, "CA");
+console.log(JSON.stringify({ got, via: el.via, events: el.events.join(",") }));
+`, setValueJS))
+	if res["got"] != "CA" || res["via"] != "select" || res["events"] != "input,change" {
+		t.Fatalf("unexpected result: %v", res)
+	}
+}
+
+func TestUncheckRadio(t *testing.T) {
+	res := runSnapshotJS(t, fmt.Sprintf(`
+globalThis.Event = class { constructor(type) { this.type = type; } };
+const make = (checked) => {
+  const el = { checked, events: [] };
+  el.dispatchEvent = (e) => el.events.push(e.type);
+  return el;
+};
+const on = make(true), off = make(false);
+(%[1]s)(on);
+(%[1]s)(off);
+console.log(JSON.stringify({ on: on.checked, onEvents: on.events.length, offEvents: off.events.length }));
+`, uncheckRadioJS))
+	if res["on"] != false || res["onEvents"] != float64(2) || res["offEvents"] != float64(0) {
+		t.Fatalf("unexpected result: %v", res)
+	}
+}
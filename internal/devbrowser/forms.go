@@ -0,0 +1,494 @@
+package devbrowser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type FormOption struct {
+	Value    string `json:"value"`
+	Label    string `json:"label,omitempty"`
+	Ref      string `json:"ref,omitempty"`
+	Selected bool   `json:"selected"`
+}
+
+type FormField struct {
+	Ref               string       `json:"ref"`
+	Tag               string       `json:"tag"`
+	Type              string       `json:"type"`
+	Name              string       `json:"name,omitempty"`
+	ID                string       `json:"id,omitempty"`
+	Label             string       `json:"label,omitempty"`
+	Placeholder       string       `json:"placeholder,omitempty"`
+	Value             interface{}  `json:"value"`
+	Required          bool         `json:"required"`
+	Invalid           bool         `json:"invalid"`
+	ValidationMessage string       `json:"validation_message,omitempty"`
+	Disabled          bool         `json:"disabled,omitempty"`
+	Options           []FormOption `json:"options,omitempty"`
+}
+
+type FormInfo struct {
+	Index  int         `json:"index"`
+	Ref    string      `json:"ref,omitempty"`
+	ID     string      `json:"id,omitempty"`
+	Name   string      `json:"name,omitempty"`
+	Action string      `json:"action,omitempty"`
+	Method string      `json:"method,omitempty"`
+	Fields []FormField `json:"fields"`
+}
+
+// FormValue is one fill_form entry: a field's label, name, id or
+// placeholder and the value to put in it.
+type FormValue struct {
+	Field string
+	Value interface{}
+}
+
+type FillFormOptions struct {
+	Form      string
+	TimeoutMs int
+	// PageOrder fills fields in the order they appear on the page rather
+	// than the order of the values (used for the object form, whose key
+	// order is lost in decoding).
+	PageOrder bool
+}
+
+type FillFormResult struct {
+	Filled    []map[string]interface{} `json:"filled"`
+	Unmatched []string                 `json:"unmatched"`
+	Failed    []map[string]interface{} `json:"failed"`
+}
+
+const collectFormsJS = `() => {
+  const norm = (t) => (t || "").replace(/\s+/g, " ").trim();
+  const register = globalThis.__devBrowser_registerRef;
+
+  function textOf(ids) {
+    return ids.split(/\s+/).filter(Boolean)
+      .map((id) => document.getElementById(id))
+      .filter(Boolean)
+      .map((n) => norm(n.innerText || n.textContent))
+      .join(" ");
+  }
+
+  function labelOf(el) {
+    const aria = norm(el.getAttribute("aria-label"));
+    if (aria) return aria;
+    const by = el.getAttribute("aria-labelledby");
+    if (by) {
+      const t = textOf(by);
+      if (t) return t;
+    }
+    if (el.labels && el.labels.length) {
+      const t = Array.from(el.labels).map((l) => norm(l.innerText || l.textContent)).filter(Boolean).join(" ");
+      if (t) return t;
+    }
+    return norm(el.getAttribute("placeholder")) || norm(el.getAttribute("title"));
+  }
+
+  function groupLabel(radios) {
+    const first = radios[0];
+    const group = first.closest("[role=radiogroup], fieldset");
+    if (group && radios.every((r) => group.contains(r))) {
+      const aria = norm(group.getAttribute("aria-label")) || textOf(group.getAttribute("aria-labelledby") || "");
+      if (aria) return aria;
+      const legend = group.querySelector("legend");
+      if (legend) return norm(legend.innerText || legend.textContent);
+    }
+    return "";
+  }
+
+  function validity(el) {
+    const v = el.validity;
+    return {
+      required: !!el.required || el.getAttribute("aria-required") === "true",
+      invalid: (v ? !v.valid : false) || el.getAttribute("aria-invalid") === "true",
+      validation_message: el.validationMessage || "",
+      disabled: !!el.disabled,
+    };
+  }
+
+  const skip = new Set(["hidden", "submit", "button", "reset", "image"]);
+  function fieldsOf(controls) {
+    const fields = [];
+    const radioGroups = new Map();
+    for (const el of controls) {
+      const tag = el.tagName.toLowerCase();
+      if (tag !== "input" && tag !== "select" && tag !== "textarea") continue;
+      const type = tag === "input" ? (el.type || "text").toLowerCase() : (tag === "select" ? (el.multiple ? "select-multiple" : "select") : "textarea");
+      if (skip.has(type)) continue;
+
+      if (type === "radio") {
+        const key = el.name || ("#" + (el.id || fields.length));
+        let group = radioGroups.get(key);
+        if (!group) {
+          group = { radios: [], field: null };
+          radioGroups.set(key, group);
+          group.field = { tag, type, name: el.name || "", id: "", ref: "", options: [] };
+          fields.push(group.field);
+        }
+        group.radios.push(el);
+        continue;
+      }
+
+      const field = {
+        ref: register(el),
+        tag,
+        type,
+        name: el.name || "",
+        id: el.id || "",
+        label: labelOf(el),
+        placeholder: el.getAttribute("placeholder") || "",
+        ...validity(el),
+      };
+      if (type === "checkbox") {
+        field.value = !!el.checked;
+      } else if (tag === "select") {
+        field.options = Array.from(el.options).map((o) => ({ value: o.value, label: norm(o.label || o.text), selected: o.selected }));
+        field.value = el.multiple ? field.options.filter((o) => o.selected).map((o) => o.value) : el.value;
+      } else {
+        field.value = el.value;
+      }
+      fields.push(field);
+    }
+
+    for (const group of radioGroups.values()) {
+      const f = group.field;
+      f.ref = register(group.radios[0]);
+      f.label = groupLabel(group.radios) || f.name;
+      const checked = group.radios.find((r) => r.checked);
+      f.value = checked ? checked.value : "";
+      Object.assign(f, validity(group.radios[0]));
+      f.options = group.radios.map((r) => ({ value: r.value, label: labelOf(r), ref: register(r), selected: r.checked }));
+    }
+    return fields;
+  }
+
+  const forms = [];
+  const owned = new Set();
+  Array.from(document.forms).forEach((form, i) => {
+    const controls = Array.from(form.elements);
+    controls.forEach((c) => owned.add(c));
+    forms.push({
+      index: i + 1,
+      ref: register(form),
+      id: form.id || "",
+      name: form.getAttribute("name") || "",
+      action: form.getAttribute("action") ? form.action : "",
+      method: (form.getAttribute("method") || "get").toLowerCase(),
+      fields: fieldsOf(controls),
+    });
+  });
+  const loose = Array.from(document.querySelectorAll("input, select, textarea")).filter((el) => !owned.has(el) && !el.form);
+  if (loose.length) {
+    const fields = fieldsOf(loose);
+    if (fields.length) forms.push({ index: 0, fields });
+  }
+  return forms;
+}`
+
+func CollectForms(page playwright.Page) ([]FormInfo, error) {
+	if err := ensureInjected(page, "simple"); err != nil {
+		return nil, err
+	}
+	raw, err := page.Evaluate(collectFormsJS)
+	if err != nil {
+		return nil, err
+	}
+	forms := []FormInfo{}
+	if err := decodeJSResult(raw, &forms); err != nil {
+		return nil, fmt.Errorf("unexpected forms result: %w", err)
+	}
+	return forms, nil
+}
+
+// selectForms narrows forms to the one named by sel: a 1-based index, an id
+// or a name. An empty selector keeps every form (including loose fields).
+func selectForms(forms []FormInfo, sel string) ([]FormInfo, error) {
+	sel = strings.TrimSpace(sel)
+	if sel == "" {
+		return forms, nil
+	}
+	idx, idxErr := strconv.Atoi(sel)
+	for _, form := range forms {
+		if (idxErr == nil && form.Index == idx) || (form.ID != "" && form.ID == sel) || (form.Name != "" && form.Name == sel) {
+			return []FormInfo{form}, nil
+		}
+	}
+	return nil, invalidArgf("form '%s' not found", sel)
+}
+
+// matchFormField finds the field a fill key refers to. Exact matches on
+// label, name, id or placeholder win over a case-insensitive label prefix.
+// Either way the key must name a single field. ok is false when nothing
+// matches.
+func matchFormField(fields []FormField, key string) (FormField, bool, error) {
+	want := normalizeFieldKey(key)
+	if want == "" {
+		return FormField{}, false, nil
+	}
+	exact := []FormField{}
+	for _, f := range fields {
+		for _, candidate := range []string{f.Label, f.Name, f.ID, f.Placeholder} {
+			if candidate != "" && normalizeFieldKey(candidate) == want {
+				exact = append(exact, f)
+				break
+			}
+		}
+	}
+	switch len(exact) {
+	case 0:
+	case 1:
+		return exact[0], true, nil
+	default:
+		return FormField{}, false, invalidArgf("'%s' matches several fields: %s; use a name or id, or pick the form", key, describeFormFields(exact))
+	}
+	matches := []FormField{}
+	for _, f := range fields {
+		label := normalizeFieldKey(f.Label)
+		if label != "" && strings.HasPrefix(label, want) {
+			matches = append(matches, f)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return FormField{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	}
+	return FormField{}, false, invalidArgf("'%s' matches several fields: %s; use the full label, name or id", key, describeFormFields(matches))
+}
+
+func describeFormFields(fields []FormField) string {
+	parts := []string{}
+	for _, f := range fields {
+		desc := fmt.Sprintf("%q (%s", f.Label, f.Ref)
+		if f.Name != "" {
+			desc += ", name=" + f.Name
+		}
+		if f.ID != "" {
+			desc += ", id=" + f.ID
+		}
+		parts = append(parts, desc+")")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func normalizeFieldKey(s string) string {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	return strings.TrimRight(s, " *:")
+}
+
+// parseFormValues reads fill_form values: an object of field to value, or a
+// list of {"field": ..., "value": ...} filled in list order. The object form
+// is filled in page order; the second result reports which form was given.
+func parseFormValues(raw interface{}) ([]FormValue, bool, error) {
+	switch val := raw.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		if len(keys) == 0 {
+			return nil, false, invalidArgf("expected non-empty 'values'")
+		}
+		sort.Strings(keys)
+		out := make([]FormValue, 0, len(keys))
+		for _, key := range keys {
+			out = append(out, FormValue{Field: key, Value: val[key]})
+		}
+		return out, true, nil
+	case []interface{}:
+		if len(val) == 0 {
+			return nil, false, invalidArgf("expected non-empty 'values'")
+		}
+		out := make([]FormValue, 0, len(val))
+		for i, item := range val {
+			entry, ok := item.(map[string]interface{})
+			field, _ := entry["field"].(string)
+			if !ok || strings.TrimSpace(field) == "" {
+				return nil, false, invalidArgf("values[%d] must be an object with a non-empty 'field'", i)
+			}
+			out = append(out, FormValue{Field: field, Value: entry["value"]})
+		}
+		return out, false, nil
+	}
+	return nil, false, invalidArgf("expected object or list for 'values'")
+}
+
+// FillForm matches every value to a field before filling any, so an
+// ambiguous key or two keys naming the same field fail the whole call.
+func FillForm(page playwright.Page, values []FormValue, opts FillFormOptions) (*FillFormResult, error) {
+	forms, err := CollectForms(page)
+	if err != nil {
+		return nil, err
+	}
+	forms, err = selectForms(forms, opts.Form)
+	if err != nil {
+		return nil, err
+	}
+	fields := []FormField{}
+	for _, form := range forms {
+		fields = append(fields, form.Fields...)
+	}
+
+	plan, unmatched, err := planFormFill(fields, values, opts.PageOrder)
+	if err != nil {
+		return nil, err
+	}
+	res := &FillFormResult{Filled: []map[string]interface{}{}, Unmatched: unmatched, Failed: []map[string]interface{}{}}
+	for _, step := range plan {
+		action, err := fillFormField(page, step.field, step.value.Value, opts.TimeoutMs)
+		if err != nil {
+			res.Failed = append(res.Failed, map[string]interface{}{"key": step.value.Field, "ref": step.field.Ref, "type": step.field.Type, "error": err.Error()})
+			continue
+		}
+		res.Filled = append(res.Filled, map[string]interface{}{"key": step.value.Field, "ref": step.field.Ref, "type": step.field.Type, "action": action})
+	}
+	return res, nil
+}
+
+type formFillStep struct {
+	value FormValue
+	field FormField
+	index int
+}
+
+// planFormFill pairs values with fields and returns them in fill order,
+// plus the keys that matched nothing.
+func planFormFill(fields []FormField, values []FormValue, pageOrder bool) ([]formFillStep, []string, error) {
+	index := map[string]int{}
+	for i, f := range fields {
+		index[f.Ref] = i
+	}
+	plan := []formFillStep{}
+	unmatched := []string{}
+	claimed := map[string]string{}
+	for _, v := range values {
+		field, ok, err := matchFormField(fields, v.Field)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			unmatched = append(unmatched, v.Field)
+			continue
+		}
+		if other, dup := claimed[field.Ref]; dup {
+			return nil, nil, invalidArgf("'%s' and '%s' both match field %q (%s)", other, v.Field, field.Label, field.Ref)
+		}
+		claimed[field.Ref] = v.Field
+		plan = append(plan, formFillStep{value: v, field: field, index: index[field.Ref]})
+	}
+	if pageOrder {
+		sort.SliceStable(plan, func(a, b int) bool { return plan[a].index < plan[b].index })
+	}
+	return plan, unmatched, nil
+}
+
+func fillFormField(page playwright.Page, field FormField, value interface{}, timeoutMs int) (string, error) {
+	timeout := playwright.Float(float64(timeoutMs))
+	switch field.Type {
+	case "radio":
+		want := formValueString(value)
+		for _, opt := range field.Options {
+			if opt.Value == want || strings.EqualFold(opt.Label, want) {
+				el, err := SelectRef(page, opt.Ref, "simple")
+				if err != nil {
+					return "", err
+				}
+				defer el.Dispose()
+				return "check", el.Check(playwright.ElementHandleCheckOptions{Timeout: timeout})
+			}
+		}
+		return "", fmt.Errorf("no radio option matches '%s'", want)
+	}
+
+	el, err := SelectRef(page, field.Ref, "simple")
+	if err != nil {
+		return "", err
+	}
+	defer el.Dispose()
+
+	switch field.Type {
+	case "checkbox":
+		checked, err := formValueBool(value)
+		if err != nil {
+			return "", err
+		}
+		return "set_checked", el.SetChecked(checked, playwright.ElementHandleSetCheckedOptions{Timeout: timeout})
+	case "select", "select-multiple":
+		wanted := formValueStrings(value)
+		opts := []string{}
+		for _, w := range wanted {
+			matched := false
+			for _, opt := range field.Options {
+				if opt.Value == w || strings.EqualFold(opt.Label, w) {
+					opts = append(opts, opt.Value)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return "", fmt.Errorf("no option matches '%s'", w)
+			}
+		}
+		_, err := el.SelectOption(playwright.SelectOptionValues{Values: &opts}, playwright.ElementHandleSelectOptionOptions{Timeout: timeout})
+		return "select_option", err
+	default:
+		fill, err := SmartFill(page, el, formValueString(value), "auto", timeoutMs)
+		return fill.Strategy, err
+	}
+}
+
+func formValueString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func formValueStrings(v interface{}) []string {
+	if arr, ok := v.([]interface{}); ok {
+		out := make([]string, 0, len(arr))
+		for _, item := range arr {
+			out = append(out, formValueString(item))
+		}
+		return out
+	}
+	if arr, ok := v.([]string); ok {
+		return arr
+	}
+	return []string{formValueString(v)}
+}
+
+func formValueBool(v interface{}) (bool, error) {
+	switch t := v.(type) {
+	case bool:
+		return t, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(t)) {
+		case "true", "yes", "on", "1", "checked":
+			return true, nil
+		case "false", "no", "off", "0", "unchecked", "":
+			return false, nil
+		}
+	case float64:
+		return t != 0, nil
+	}
+	return false, invalidArgf("checkbox value must be boolean")
+}
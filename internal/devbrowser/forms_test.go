@@ -0,0 +1,141 @@
+package devbrowser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchFormField(t *testing.T) {
+	fields := []FormField{
+		{Ref: "e1", Type: "email", Name: "user_email", Label: "Email address *"},
+		{Ref: "e2", Type: "password", Name: "pw", Label: "Password"},
+		{Ref: "e3", Type: "text", ID: "zip", Placeholder: "Postal code"},
+		{Ref: "e4", Type: "password", Name: "pw2", Label: "Password confirmation"},
+	}
+	tests := []struct {
+		key string
+		ref string
+	}{
+		{"email address", "e1"},
+		{"user_email", "e1"},
+		{"Password", "e2"},
+		{"zip", "e3"},
+		{"postal code", "e3"},
+		{"Email", "e1"},
+		{"password confirmation", "e4"},
+	}
+	for _, tt := range tests {
+		f, ok, err := matchFormField(fields, tt.key)
+		if err != nil || !ok || f.Ref != tt.ref {
+			t.Fatalf("key %q: expected %s, got %+v (ok=%v, err=%v)", tt.key, tt.ref, f, ok, err)
+		}
+	}
+	if _, ok, err := matchFormField(fields, "Phone"); ok || err != nil {
+		t.Fatalf("expected no match for Phone (err=%v)", err)
+	}
+	// "Pass" is a prefix of two labels.
+	if _, _, err := matchFormField(fields, "Pass"); err == nil {
+		t.Fatalf("expected an ambiguity error for Pass")
+	}
+}
+
+func TestMatchFormFieldRejectsDuplicateLabels(t *testing.T) {
+	fields := []FormField{
+		{Ref: "e1", Type: "email", Name: "billing_email", Label: "Email"},
+		{Ref: "e2", Type: "email", Name: "shipping_email", Label: "Email"},
+	}
+	_, ok, err := matchFormField(fields, "Email")
+	var te *ToolError
+	if ok || !errors.As(err, &te) || te.Code != ErrInvalidArgument {
+		t.Fatalf("expected invalid_argument for a label two fields share, got ok=%v err=%v", ok, err)
+	}
+	if !strings.Contains(err.Error(), "e1") || !strings.Contains(err.Error(), "e2") {
+		t.Fatalf("error should list both candidates: %v", err)
+	}
+	if f, ok, err := matchFormField(fields, "shipping_email"); err != nil || !ok || f.Ref != "e2" {
+		t.Fatalf("name should still pick one field, got %+v ok=%v err=%v", f, ok, err)
+	}
+}
+
+func TestSelectFormsUnknownForm(t *testing.T) {
+	_, err := selectForms([]FormInfo{{Index: 0, ID: "login"}}, "signup")
+	var te *ToolError
+	if !errors.As(err, &te) || te.Code != ErrInvalidArgument {
+		t.Fatalf("expected invalid_argument, got %v", err)
+	}
+}
+
+func TestParseFormValues(t *testing.T) {
+	values, pageOrder, err := parseFormValues(map[string]interface{}{"Zip": "1", "Email": "a@b.c"})
+	if err != nil || !pageOrder || len(values) != 2 || values[0].Field != "Email" {
+		t.Fatalf("object form: %+v %v %v", values, pageOrder, err)
+	}
+	values, pageOrder, err = parseFormValues([]interface{}{
+		map[string]interface{}{"field": "Zip", "value": "1"},
+		map[string]interface{}{"field": "Email", "value": "a@b.c"},
+	})
+	if err != nil || pageOrder || values[0].Field != "Zip" || values[1].Value != "a@b.c" {
+		t.Fatalf("list form: %+v %v %v", values, pageOrder, err)
+	}
+	for _, bad := range []interface{}{nil, "x", map[string]interface{}{}, []interface{}{}, []interface{}{map[string]interface{}{"value": 1}}} {
+		if _, _, err := parseFormValues(bad); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
+
+func TestPlanFormFill(t *testing.T) {
+	fields := []FormField{
+		{Ref: "e1", Name: "email", Label: "Email"},
+		{Ref: "e2", Name: "country", Label: "Country"},
+		{Ref: "e3", Name: "state", Label: "State"},
+	}
+	values := []FormValue{{Field: "State", Value: "ON"}, {Field: "Phone"}, {Field: "Country", Value: "Canada"}}
+	plan, unmatched, err := planFormFill(fields, values, false)
+	if err != nil || len(plan) != 2 || plan[0].field.Ref != "e3" || plan[1].field.Ref != "e2" {
+		t.Fatalf("caller order: %+v %v", plan, err)
+	}
+	if len(unmatched) != 1 || unmatched[0] != "Phone" {
+		t.Fatalf("unmatched = %v", unmatched)
+	}
+	plan, _, _ = planFormFill(fields, values, true)
+	if plan[0].field.Ref != "e2" || plan[1].field.Ref != "e3" {
+		t.Fatalf("page order: %+v", plan)
+	}
+	if _, _, err := planFormFill(fields, []FormValue{{Field: "email"}, {Field: "Email"}}, false); err == nil {
+		t.Fatal("expected an error for two keys naming one field")
+	}
+}
+
+func TestSelectForms(t *testing.T) {
+	forms := []FormInfo{{Index: 1, ID: "login"}, {Index: 2, Name: "signup"}, {Index: 0}}
+	if got, err := selectForms(forms, ""); err != nil || len(got) != 3 {
+		t.Fatalf("expected all forms, got %+v (%v)", got, err)
+	}
+	if got, err := selectForms(forms, "2"); err != nil || got[0].Name != "signup" {
+		t.Fatalf("expected form 2, got %+v (%v)", got, err)
+	}
+	if got, err := selectForms(forms, "login"); err != nil || got[0].Index != 1 {
+		t.Fatalf("expected login form, got %+v (%v)", got, err)
+	}
+	if _, err := selectForms(forms, "missing"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestFormValueBool(t *testing.T) {
+	for _, v := range []interface{}{true, "yes", "on", "1", 1.0} {
+		if b, err := formValueBool(v); err != nil || !b {
+			t.Fatalf("expected %v to be true", v)
+		}
+	}
+	for _, v := range []interface{}{false, "no", "", 0.0} {
+		if b, err := formValueBool(v); err != nil || b {
+			t.Fatalf("expected %v to be false", v)
+		}
+	}
+	if _, err := formValueBool("maybe"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
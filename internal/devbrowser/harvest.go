@@ -0,0 +1,245 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type HarvestOptions struct {
+	Container       TargetSpec
+	RowSelector     string
+	InteractiveOnly bool
+	MaxItems        int
+	MaxDurationMs   int
+	IdleRounds      int
+	SettleMs        int
+	ScrollPx        float64
+}
+
+type HarvestResult struct {
+	Items      []map[string]interface{}
+	Rounds     int
+	StopReason string
+}
+
+const harvestRowsJS = `(root, sel) => {
+  const norm = (t) => (t || "").replace(/\s+/g, " ").trim();
+  return Array.from((root || document).querySelectorAll(sel))
+    .map((el) => norm(el.innerText || el.textContent))
+    .filter(Boolean);
+}`
+
+// harvestSet accumulates unique entries in first-seen order.
+type harvestSet struct {
+	index map[string]int
+	items []map[string]interface{}
+	// lastSeen is the round each entry was last collected in.
+	lastSeen []int
+	// repeated holds fingerprints seen more than once in one round, such as
+	// a "Reply" button per comment. Those entries are told apart by the
+	// entry before them.
+	repeated map[string]bool
+}
+
+func newHarvestSet() *harvestSet {
+	return &harvestSet{index: map[string]int{}, repeated: map[string]bool{}}
+}
+
+// add merges the entries collected in round and returns how many were new.
+// An entry seen again takes the newer ref. A repeated entry before the
+// batch's first unrepeated one belongs to an entry scrolled out of view, so
+// after the first round it is skipped; the round that still showed that
+// entry already collected it.
+func (h *harvestSet) add(batch []map[string]interface{}, round int) int {
+	keys := make([]string, len(batch))
+	counts := map[string]int{}
+	for i, item := range batch {
+		keys[i] = harvestFingerprint(item)
+		if keys[i] != "" {
+			counts[keys[i]]++
+		}
+	}
+	for key, n := range counts {
+		if n > 1 {
+			h.repeated[key] = true
+		}
+	}
+
+	added := 0
+	anchor := ""
+	occurrence := map[string]int{}
+	for i, item := range batch {
+		key := keys[i]
+		if key == "" {
+			continue
+		}
+		if h.repeated[key] {
+			if anchor == "" && round > 1 {
+				continue
+			}
+			base := key + "\x00" + anchor
+			occurrence[base]++
+			key = fmt.Sprintf("%s\x00%d", base, occurrence[base])
+		} else {
+			anchor = key
+		}
+		if idx, ok := h.index[key]; ok {
+			if ref, ok := item["ref"]; ok {
+				h.items[idx]["ref"] = ref
+			}
+			h.lastSeen[idx] = round
+			continue
+		}
+		h.index[key] = len(h.items)
+		h.items = append(h.items, item)
+		h.lastSeen = append(h.lastSeen, round)
+		added++
+	}
+	return added
+}
+
+// dropStaleRefs removes refs from entries not collected in the last round.
+// Each snapshot replaces the ref table, and virtualized lists reuse nodes,
+// so an older ref is either unknown or now points at different content.
+func (h *harvestSet) dropStaleRefs(lastRound int) {
+	for i, item := range h.items {
+		if h.lastSeen[i] != lastRound {
+			delete(item, "ref")
+		}
+	}
+}
+
+// harvestFingerprint identifies an entry independent of its ref, since
+// virtualized lists recycle DOM nodes as they scroll.
+func harvestFingerprint(item map[string]interface{}) string {
+	if text, ok := item["text"].(string); ok {
+		return "text\x00" + text
+	}
+	role, _ := item["role"].(string)
+	name, _ := item["name"].(string)
+	heading, _ := item["heading"].(string)
+	if role == "" && name == "" {
+		return ""
+	}
+	return strings.Join([]string{role, name, heading}, "\x00")
+}
+
+func Harvest(page playwright.Page, opts HarvestOptions) (*HarvestResult, error) {
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = 500
+	}
+	if opts.IdleRounds <= 0 {
+		opts.IdleRounds = 3
+	}
+
+	var container playwright.ElementHandle
+	if opts.Container.isSet() {
+		el, err := resolveElement(page, opts.Container)
+		if err != nil {
+			return nil, err
+		}
+		defer el.Dispose()
+		container = el
+	}
+
+	collect := func() ([]map[string]interface{}, error) {
+		if strings.TrimSpace(opts.RowSelector) != "" {
+			var raw interface{}
+			var err error
+			if container != nil {
+				raw, err = container.Evaluate(harvestRowsJS, opts.RowSelector)
+			} else {
+				raw, err = page.Evaluate("(sel) => ("+harvestRowsJS+")(null, sel)", opts.RowSelector)
+			}
+			if err != nil {
+				return nil, err
+			}
+			rows := []map[string]interface{}{}
+			for _, text := range toStringSlice(raw) {
+				rows = append(rows, map[string]interface{}{"text": text})
+			}
+			return rows, nil
+		}
+		snap, err := GetSnapshot(page, SnapshotOptions{
+			Root:            container,
+			Engine:          "simple",
+			Format:          "list",
+			InteractiveOnly: opts.InteractiveOnly,
+			IncludeHeadings: true,
+			MaxItems:        opts.MaxItems,
+			MaxChars:        1_000_000,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return snap.Items, nil
+	}
+
+	scrollOpts := ScrollOptions{}
+	if opts.ScrollPx > 0 {
+		scrollOpts = ScrollOptions{DY: opts.ScrollPx, HasDelta: true}
+	}
+
+	set := newHarvestSet()
+	deadline := time.Now().Add(time.Duration(opts.MaxDurationMs) * time.Millisecond)
+	idle := 0
+	rounds := 0
+	reason := ""
+	for {
+		batch, err := collect()
+		if err != nil {
+			return nil, err
+		}
+		rounds++
+		if set.add(batch, rounds) == 0 {
+			idle++
+		} else {
+			idle = 0
+		}
+		if len(set.items) >= opts.MaxItems {
+			set.items = set.items[:opts.MaxItems]
+			reason = "max_items"
+			break
+		}
+		if idle >= opts.IdleRounds {
+			reason = "no_new_content"
+			break
+		}
+		if opts.MaxDurationMs > 0 && time.Now().After(deadline) {
+			reason = "max_duration"
+			break
+		}
+
+		if container != nil {
+			if _, err := container.Evaluate(scrollJS, scrollPayload(scrollOpts)); err != nil {
+				return nil, err
+			}
+		} else if _, err := Scroll(page, TargetSpec{}, scrollOpts); err != nil {
+			return nil, err
+		}
+		if opts.SettleMs > 0 {
+			page.WaitForTimeout(float64(opts.SettleMs))
+		}
+	}
+	set.dropStaleRefs(rounds)
+	return &HarvestResult{Items: set.items, Rounds: rounds, StopReason: reason}, nil
+}
+
+func writeHarvestArtifact(artifactDir, pathArg string, items []map[string]interface{}) (string, error) {
+	path, err := SafeArtifactPath(artifactDir, pathArg, fmt.Sprintf("harvest-%d.json", NowMS()))
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := osWriteFile(path, data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,118 @@
+package devbrowser
+
+import "testing"
+
+func TestHarvestSetDedupe(t *testing.T) {
+	set := newHarvestSet()
+	first := []map[string]interface{}{
+		{"ref": "e1", "role": "link", "name": "Row 1"},
+		{"ref": "e2", "role": "link", "name": "Row 2"},
+	}
+	if added := set.add(first, 1); added != 2 {
+		t.Fatalf("expected 2 new items, got %d", added)
+	}
+	// Recycled nodes get new refs but keep role/name.
+	second := []map[string]interface{}{
+		{"ref": "e9", "role": "link", "name": "Row 2"},
+		{"ref": "e10", "role": "link", "name": "Row 3"},
+	}
+	if added := set.add(second, 2); added != 1 {
+		t.Fatalf("expected 1 new item, got %d", added)
+	}
+	if len(set.items) != 3 || set.items[2]["name"] != "Row 3" {
+		t.Fatalf("unexpected items: %+v", set.items)
+	}
+}
+
+func TestHarvestFingerprintRows(t *testing.T) {
+	a := harvestFingerprint(map[string]interface{}{"text": "Order #1 shipped"})
+	b := harvestFingerprint(map[string]interface{}{"role": "row", "name": "Order #1 shipped"})
+	if a == "" || a == b {
+		t.Fatalf("expected distinct text fingerprint, got %q and %q", a, b)
+	}
+	if harvestFingerprint(map[string]interface{}{}) != "" {
+		t.Fatalf("expected empty fingerprint for empty item")
+	}
+}
+
+func TestHarvestSetKeepsRepeatedButtons(t *testing.T) {
+	set := newHarvestSet()
+	first := []map[string]interface{}{
+		{"ref": "e1", "role": "link", "name": "Post A"},
+		{"ref": "e2", "role": "button", "name": "Reply"},
+		{"ref": "e3", "role": "link", "name": "Post B"},
+		{"ref": "e4", "role": "button", "name": "Reply"},
+	}
+	if added := set.add(first, 1); added != 4 {
+		t.Fatalf("expected 4 new items, got %d: %+v", added, set.items)
+	}
+	// Scrolled by one post: B's reply is the same entry, C's is new.
+	second := []map[string]interface{}{
+		{"ref": "e3", "role": "link", "name": "Post B"},
+		{"ref": "e4", "role": "button", "name": "Reply"},
+		{"ref": "e5", "role": "link", "name": "Post C"},
+		{"ref": "e2", "role": "button", "name": "Reply"},
+	}
+	if added := set.add(second, 2); added != 2 {
+		t.Fatalf("expected 2 new items, got %d: %+v", added, set.items)
+	}
+	if len(set.items) != 6 {
+		t.Fatalf("expected 6 items, got %+v", set.items)
+	}
+}
+
+func TestHarvestSetDropsStaleRefs(t *testing.T) {
+	set := newHarvestSet()
+	set.add([]map[string]interface{}{
+		{"ref": "e1", "role": "link", "name": "Row 1"},
+		{"ref": "e2", "role": "link", "name": "Row 2"},
+	}, 1)
+	// e1's node was recycled for Row 3; Row 2 got a new ref.
+	set.add([]map[string]interface{}{
+		{"ref": "e7", "role": "link", "name": "Row 2"},
+		{"ref": "e1", "role": "link", "name": "Row 3"},
+	}, 2)
+	set.dropStaleRefs(2)
+	if _, ok := set.items[0]["ref"]; ok {
+		t.Fatalf("Row 1 kept a stale ref: %+v", set.items[0])
+	}
+	if set.items[1]["ref"] != "e7" || set.items[2]["ref"] != "e1" {
+		t.Fatalf("expected current refs, got %+v", set.items)
+	}
+}
+
+func TestHarvestSetSkipsUnanchoredRepeatsAfterFirstRound(t *testing.T) {
+	set := newHarvestSet()
+	first := []map[string]interface{}{
+		{"ref": "e1", "role": "link", "name": "Post A"},
+		{"ref": "e2", "role": "button", "name": "Reply"},
+		{"ref": "e3", "role": "link", "name": "Post B"},
+		{"ref": "e4", "role": "button", "name": "Reply"},
+	}
+	if added := set.add(first, 1); added != 4 {
+		t.Fatalf("expected 4 new items, got %d: %+v", added, set.items)
+	}
+	// Post B scrolled out; the batch starts with its reply.
+	second := []map[string]interface{}{
+		{"ref": "e4", "role": "button", "name": "Reply"},
+		{"ref": "e5", "role": "link", "name": "Post C"},
+		{"ref": "e6", "role": "button", "name": "Reply"},
+	}
+	if added := set.add(second, 2); added != 2 {
+		t.Fatalf("expected Post C and its reply, got %d new: %+v", added, set.items)
+	}
+	if len(set.items) != 6 {
+		t.Fatalf("expected 6 items, got %+v", set.items)
+	}
+
+	// At the top of the list nothing precedes the first reply, so it is kept.
+	set = newHarvestSet()
+	top := []map[string]interface{}{
+		{"ref": "e1", "role": "button", "name": "Reply"},
+		{"ref": "e2", "role": "link", "name": "Post A"},
+		{"ref": "e3", "role": "button", "name": "Reply"},
+	}
+	if added := set.add(top, 1); added != 3 {
+		t.Fatalf("expected 3 new items, got %d: %+v", added, set.items)
+	}
+}
@@ -32,12 +32,14 @@ type BrowserHost struct {
 	registry map[string]pageHolder
 	userData string
 	logs     *consoleStore
+	network  *networkStore
 }
 
 type pageHolder struct {
 	page          playwright.Page
 	targetID      string
 	consoleHooked bool
+	networkHooked bool
 }
 
 func NewBrowserHost(profile string, headless bool, cdpPort int, window *WindowSize) *BrowserHost {
@@ -54,6 +56,7 @@ func NewBrowserHost(profile string, headless bool, cdpPort int, window *WindowSi
 		registry: make(map[string]pageHolder),
 		userData: filepath.Join(stateBase, "chromium-profile"),
 		logs:     newConsoleStore(0),
+		network:  newNetworkStore(0),
 	}
 }
 
@@ -96,6 +99,9 @@ func (b *BrowserHost) Stop() {
 	if b.logs != nil {
 		b.logs.clearAll()
 	}
+	if b.network != nil {
+		b.network.clearAll()
+	}
 }
 
 func (b *BrowserHost) ListPages() []string {
@@ -124,6 +130,9 @@ func (b *BrowserHost) ClosePage(name string) bool {
 	if b.logs != nil {
 		b.logs.clear(name)
 	}
+	if b.network != nil {
+		b.network.clear(name)
+	}
 	return true
 }
 
@@ -141,6 +150,9 @@ func (b *BrowserHost) GetOrCreatePage(name string) (PageEntry, error) {
 		if !holder.consoleHooked {
 			b.attachConsoleLocked(name, holder.page)
 		}
+		if !holder.networkHooked {
+			b.attachNetworkLocked(name, holder.page)
+		}
 		return PageEntry{Name: name, TargetID: holder.targetID}, nil
 	}
 
@@ -154,7 +166,9 @@ func (b *BrowserHost) GetOrCreatePage(name string) (PageEntry, error) {
 		return PageEntry{}, err
 	}
 	b.registry[name] = pageHolder{page: page, targetID: tid}
+	resetSessionLog(SessionLogPath(b.profile, name))
 	b.attachConsoleLocked(name, page)
+	b.attachNetworkLocked(name, page)
 	return PageEntry{Name: name, TargetID: tid}, nil
 }
 
@@ -225,7 +239,9 @@ func (b *BrowserHost) startLocked() error {
 	b.context = context
 	b.ws = ws
 	b.registry["main"] = pageHolder{page: mainPage, targetID: tid}
+	resetSessionLog(SessionLogPath(b.profile, "main"))
 	b.attachConsoleLocked("main", mainPage)
+	b.attachNetworkLocked("main", mainPage)
 
 	for _, pg := range pages[1:] {
 		_ = pg.Close()
@@ -257,6 +273,45 @@ func (b *BrowserHost) attachConsoleLocked(name string, page playwright.Page) {
 	b.registry[name] = holder
 }
 
+func (b *BrowserHost) attachNetworkLocked(name string, page playwright.Page) {
+	holder, ok := b.registry[name]
+	if !ok || holder.networkHooked || b.network == nil {
+		return
+	}
+	store := b.network
+	page.OnRequest(func(req playwright.Request) {
+		store.started(name, req)
+	})
+	page.OnResponse(func(resp playwright.Response) {
+		store.responded(name, resp.Request(), resp.Status())
+	})
+	page.OnRequestFinished(func(req playwright.Request) {
+		store.ended(name, req, false)
+	})
+	page.OnRequestFailed(func(req playwright.Request) {
+		store.ended(name, req, true)
+	})
+	page.OnWebSocket(func(ws playwright.WebSocket) {
+		store.socketOpened(name, ws)
+		ws.OnFrameReceived(func([]byte) { store.socketFrame(name, ws) })
+		ws.OnFrameSent(func([]byte) { store.socketFrame(name, ws) })
+		ws.OnClose(func(playwright.WebSocket) { store.socketClosed(name, ws) })
+	})
+	holder.networkHooked = true
+	b.registry[name] = holder
+}
+
+func (b *BrowserHost) NetworkActivity(name string) (NetworkActivity, error) {
+	b.mu.Lock()
+	holder, ok := b.registry[name]
+	pageOk := ok && holder.page != nil && !holder.page.IsClosed()
+	b.mu.Unlock()
+	if !pageOk {
+		return NetworkActivity{}, errors.New("page not found")
+	}
+	return b.network.activity(name), nil
+}
+
 func (b *BrowserHost) ConsoleLogs(name string, since int64, limit int) ([]ConsoleEntry, int64, error) {
 	if since < 0 {
 		return nil, 0, errors.New("since must be >= 0")
@@ -0,0 +1,332 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type InspectOptions struct {
+	MaxHTMLChars int
+	Listeners    bool
+}
+
+// inspectJS reports what the page knows about el: attributes, styles that
+// decide whether it can be seen and clicked, its box and what covers it.
+const inspectJS = `(el, opts) => {
+  const clip = (s, n) => (s.length > n ? s.slice(0, n) + "…" : s);
+  const norm = (t) => (t || "").replace(/\s+/g, " ").trim();
+  const describe = globalThis.__devBrowser_describeElement;
+  const line = (node) => {
+    if (describe) {
+      const item = describe(node);
+      return { ref: item.ref, role: item.role, name: item.name, line: item.line };
+    }
+    return { line: node.tagName.toLowerCase() };
+  };
+
+  const attributes = {};
+  for (const a of el.attributes) {
+    attributes[a.name] = clip(a.value, 200);
+  }
+
+  const style = getComputedStyle(el);
+  const styles = {};
+  for (const p of ["display", "visibility", "opacity", "pointer-events", "z-index", "position", "cursor",
+    "color", "background-color", "font-family", "font-size", "font-weight"]) {
+    styles[p] = style.getPropertyValue(p);
+  }
+  let effectiveOpacity = 1;
+  for (let n = el; n && n.nodeType === 1; n = n.parentElement || (n.getRootNode() && n.getRootNode().host)) {
+    effectiveOpacity *= parseFloat(getComputedStyle(n).opacity) || 0;
+  }
+
+  const r = el.getBoundingClientRect();
+  const vw = innerWidth;
+  const vh = innerHeight;
+  const visible = r.width > 0 && r.height > 0 && style.visibility !== "hidden" && style.display !== "none";
+  const inViewport = r.bottom > 0 && r.right > 0 && r.top < vh && r.left < vw;
+
+  const hitAt = (x, y) => {
+    let hit = document.elementFromPoint(x, y);
+    while (hit && hit.shadowRoot) {
+      const inner = hit.shadowRoot.elementFromPoint(x, y);
+      if (!inner || inner === hit) break;
+      hit = inner;
+    }
+    return hit;
+  };
+  let occlusion = null;
+  if (visible && inViewport) {
+    const points = [[0.5, 0.5], [0.25, 0.25], [0.75, 0.25], [0.25, 0.75], [0.75, 0.75]];
+    let sampled = 0;
+    let covered = 0;
+    let blocker = null;
+    for (const [fx, fy] of points) {
+      const x = r.left + r.width * fx;
+      const y = r.top + r.height * fy;
+      if (x < 0 || y < 0 || x >= vw || y >= vh) continue;
+      sampled++;
+      const hit = hitAt(x, y);
+      if (hit && hit !== el && !el.contains(hit)) {
+        covered++;
+        if (!blocker) blocker = hit;
+      }
+    }
+    occlusion = { sampled_points: sampled, covered_points: covered, blocked_by: blocker ? line(blocker) : null };
+  }
+
+  const html = el.outerHTML;
+  const self = line(el);
+  const describedBy = (el.getAttribute("aria-describedby") || "").split(/\s+/).filter(Boolean)
+    .map((id) => document.getElementById(id)).filter(Boolean).map((n) => norm(n.textContent)).join(" ");
+
+  return {
+    ref: self.ref || "",
+    tag: el.tagName.toLowerCase(),
+    role: self.role || "",
+    name: self.name || "",
+    description: describedBy || norm(el.getAttribute("title")),
+    attributes,
+    text: clip(norm(el.innerText || el.textContent), 200),
+    styles,
+    effective_opacity: Math.round(effectiveOpacity * 100) / 100,
+    visible,
+    enabled: !(el.matches(":disabled") || el.getAttribute("aria-disabled") === "true"),
+    focused: document.activeElement === el,
+    box: { x: r.left, y: r.top, width: r.width, height: r.height },
+    in_viewport: inViewport,
+    viewport: { width: vw, height: vh },
+    occlusion,
+    html: opts.maxHtmlChars > 0 ? clip(html, opts.maxHtmlChars) : "",
+    html_length: html.length,
+    html_truncated: html.length > Math.max(opts.maxHtmlChars, 0),
+  };
+}`
+
+// inspectAncestorsJS lists the nodes an event on el bubbles through, so
+// delegated listeners (e.g. a framework's root handler) show up too.
+const inspectAncestorsJS = `function() {
+  const out = [];
+  let n = this.parentNode;
+  while (n) {
+    if (n.nodeType === 11 && n.host) n = n.host;
+    out.push(n);
+    n = n.parentNode;
+  }
+  out.push(window);
+  return out;
+}`
+
+const inspectObjectGroup = "dev-browser-inspect"
+
+// InspectElement gathers a debugging report for el. Accessible name and
+// description come from Chrome's accessibility tree and listeners from
+// DOMDebugger when a CDP session is available; otherwise the report keeps
+// the DOM-derived values and says why the rest is missing.
+func InspectElement(page playwright.Page, el playwright.ElementHandle, opts InspectOptions) (map[string]interface{}, error) {
+	if err := ensureInjected(page, "simple"); err != nil {
+		return nil, err
+	}
+	raw, err := el.Evaluate(inspectJS, map[string]interface{}{"maxHtmlChars": opts.MaxHTMLChars})
+	if err != nil {
+		return nil, fmt.Errorf("inspect element: %w", err)
+	}
+	report, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected inspect result")
+	}
+
+	if err := inspectCDP(page, el, report, opts.Listeners); err != nil {
+		report["cdp_error"] = err.Error()
+	}
+	return report, nil
+}
+
+// inspectCDP fills in the accessibility and listener fields of report. The
+// element is handed to CDP through a page global, so elements inside
+// iframes are not reachable and report an error instead.
+func inspectCDP(page playwright.Page, el playwright.ElementHandle, report map[string]interface{}, listeners bool) error {
+	if _, err := el.Evaluate("(el) => { globalThis.__devBrowser_inspectTarget = el; }"); err != nil {
+		return err
+	}
+	defer page.Evaluate("() => { delete globalThis.__devBrowser_inspectTarget; }")
+
+	session, err := page.Context().NewCDPSession(page)
+	if err != nil {
+		return err
+	}
+	defer session.Detach()
+	defer session.Send("Runtime.releaseObjectGroup", map[string]interface{}{"objectGroup": inspectObjectGroup})
+
+	res, err := session.Send("Runtime.evaluate", map[string]interface{}{
+		"expression":  "globalThis.__devBrowser_inspectTarget",
+		"objectGroup": inspectObjectGroup,
+	})
+	if err != nil {
+		return err
+	}
+	objectID := cdpString(cdpObject(res, "result"), "objectId")
+	if objectID == "" {
+		return fmt.Errorf("element is not in the main frame")
+	}
+
+	if ax, err := session.Send("Accessibility.getPartialAXTree", map[string]interface{}{
+		"objectId":       objectID,
+		"fetchRelatives": false,
+	}); err == nil {
+		applyAXNode(report, ax)
+	}
+
+	if !listeners {
+		return nil
+	}
+	own, err := cdpEventListeners(session, objectID)
+	if err != nil {
+		return err
+	}
+	list := make([]map[string]interface{}, 0, len(own))
+	for _, l := range own {
+		entry := map[string]interface{}{
+			"type":        cdpString(l, "type"),
+			"use_capture": l["useCapture"] == true,
+			"passive":     l["passive"] == true,
+			"once":        l["once"] == true,
+		}
+		if handler := cdpString(cdpObject(l, "handler"), "description"); handler != "" {
+			entry["handler"] = compactSource(handler, 160)
+		}
+		list = append(list, entry)
+	}
+	report["event_listeners"] = list
+
+	ancestors, err := inspectAncestorListeners(session, objectID)
+	if err != nil {
+		return err
+	}
+	report["ancestor_listeners"] = ancestors
+	return nil
+}
+
+// inspectAncestorListeners returns the listener types registered on each
+// node el's events bubble through, skipping nodes without listeners.
+func inspectAncestorListeners(session playwright.CDPSession, objectID string) ([]map[string]interface{}, error) {
+	res, err := session.Send("Runtime.callFunctionOn", map[string]interface{}{
+		"objectId":            objectID,
+		"functionDeclaration": inspectAncestorsJS,
+		"objectGroup":         inspectObjectGroup,
+	})
+	if err != nil {
+		return nil, err
+	}
+	arrayID := cdpString(cdpObject(res, "result"), "objectId")
+	if arrayID == "" {
+		return nil, fmt.Errorf("no ancestors returned")
+	}
+	props, err := session.Send("Runtime.getProperties", map[string]interface{}{
+		"objectId":      arrayID,
+		"ownProperties": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rawProps, _ := cdpObject(props, "")["result"].([]interface{})
+
+	out := []map[string]interface{}{}
+	for _, rawProp := range rawProps {
+		prop, _ := rawProp.(map[string]interface{})
+		if prop == nil || prop["enumerable"] != true {
+			continue
+		}
+		value := cdpObject(prop, "value")
+		id := cdpString(value, "objectId")
+		if id == "" {
+			continue
+		}
+		found, err := cdpEventListeners(session, id)
+		if err != nil || len(found) == 0 {
+			continue
+		}
+		types := []string{}
+		seen := map[string]bool{}
+		for _, l := range found {
+			t := cdpString(l, "type")
+			if t != "" && !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+		out = append(out, map[string]interface{}{
+			"node":  cdpString(value, "description"),
+			"types": types,
+		})
+	}
+	return out, nil
+}
+
+func cdpEventListeners(session playwright.CDPSession, objectID string) ([]map[string]interface{}, error) {
+	res, err := session.Send("DOMDebugger.getEventListeners", map[string]interface{}{
+		"objectId":    objectID,
+		"depth":       0,
+		"objectGroup": inspectObjectGroup,
+	})
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := cdpObject(res, "")["listeners"].([]interface{})
+	out := make([]map[string]interface{}, 0, len(raw))
+	for _, item := range raw {
+		if l, ok := item.(map[string]interface{}); ok {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+// applyAXNode overrides role, name and description with the values Chrome
+// computed for the element's accessibility node.
+func applyAXNode(report map[string]interface{}, ax interface{}) {
+	nodes, _ := cdpObject(ax, "")["nodes"].([]interface{})
+	if len(nodes) == 0 {
+		return
+	}
+	node, _ := nodes[0].(map[string]interface{})
+	if node == nil {
+		return
+	}
+	if node["ignored"] == true {
+		report["ax_ignored"] = true
+	}
+	for key, field := range map[string]string{"role": "role", "name": "name", "description": "description"} {
+		if value, ok := cdpObject(node, field)["value"].(string); ok && value != "" {
+			report[key] = value
+		}
+	}
+}
+
+// cdpObject returns v[key] as an object; an empty key returns v itself.
+func cdpObject(v interface{}, key string) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	if key == "" || m == nil {
+		return m
+	}
+	child, _ := m[key].(map[string]interface{})
+	return child
+}
+
+func cdpString(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// compactSource collapses whitespace in a handler's source and cuts it to
+// max runes so listener reports stay one line each.
+func compactSource(src string, max int) string {
+	src = strings.Join(strings.Fields(src), " ")
+	runes := []rune(src)
+	if max > 0 && len(runes) > max {
+		return string(runes[:max]) + "…"
+	}
+	return src
+}
@@ -0,0 +1,39 @@
+package devbrowser
+
+import "testing"
+
+func TestApplyAXNode(t *testing.T) {
+	report := map[string]interface{}{"role": "generic", "name": "dom name", "description": ""}
+	applyAXNode(report, map[string]interface{}{
+		"nodes": []interface{}{
+			map[string]interface{}{
+				"ignored":     false,
+				"role":        map[string]interface{}{"type": "role", "value": "button"},
+				"name":        map[string]interface{}{"type": "computedString", "value": "Save draft"},
+				"description": map[string]interface{}{"type": "computedString", "value": ""},
+			},
+		},
+	})
+	if report["role"] != "button" || report["name"] != "Save draft" {
+		t.Fatalf("report = %v", report)
+	}
+	if _, ok := report["ax_ignored"]; ok {
+		t.Fatalf("unexpected ax_ignored: %v", report)
+	}
+
+	applyAXNode(report, map[string]interface{}{"nodes": []interface{}{map[string]interface{}{"ignored": true}}})
+	if report["ax_ignored"] != true || report["name"] != "Save draft" {
+		t.Fatalf("report = %v", report)
+	}
+	applyAXNode(report, nil)
+}
+
+func TestCompactSource(t *testing.T) {
+	got := compactSource("function onClick(e) {\n\n    e.preventDefault();\n}", 0)
+	if got != "function onClick(e) { e.preventDefault(); }" {
+		t.Fatalf("compactSource = %q", got)
+	}
+	if got := compactSource("() => doSomethingLong()", 8); got != "() => do…" {
+		t.Fatalf("compactSource = %q", got)
+	}
+}
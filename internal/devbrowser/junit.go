@@ -0,0 +1,91 @@
+package devbrowser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func junitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// JUnitXML renders the report with one testcase per scenario. Step timings
+// and artifact paths go to system-out so CI shows them next to the case.
+func (r *RunReport) JUnitXML() ([]byte, error) {
+	suite := junitSuite{
+		Name:      r.Name,
+		Tests:     len(r.Scenarios),
+		Failures:  r.Failed,
+		Time:      junitSeconds(r.DurationMS),
+		Timestamp: r.StartedAt.UTC().Format("2006-01-02T15:04:05"),
+	}
+	for _, sc := range r.Scenarios {
+		tc := junitCase{ClassName: r.Name, Name: sc.Name, Time: junitSeconds(sc.DurationMS)}
+		var out strings.Builder
+		for _, step := range sc.Steps {
+			fmt.Fprintf(&out, "[%s] %s (%s) %s %dms\n", step.Phase, step.Name, step.Tool, step.Status, step.DurationMS)
+			if step.Error != nil {
+				fmt.Fprintf(&out, "    %s: %s\n", step.Error.Code, step.Error.Message)
+			}
+		}
+		for _, path := range sc.Artifacts {
+			fmt.Fprintf(&out, "artifact: %s\n", path)
+		}
+		tc.SystemOut = out.String()
+		if sc.Status != stepPassed {
+			failure := &junitFailure{Message: "scenario failed", Type: string(ErrUnknown)}
+			if sc.Error != nil {
+				failure.Message = sc.Error.Message
+				failure.Type = string(sc.Error.Code)
+			}
+			failure.Body = tc.SystemOut
+			tc.Failure = failure
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	doc := junitSuites{
+		Name:     r.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
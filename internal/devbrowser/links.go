@@ -0,0 +1,211 @@
+package devbrowser
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type LinkInfo struct {
+	Href     string `json:"href"`
+	Text     string `json:"text"`
+	Rel      string `json:"rel,omitempty"`
+	Target   string `json:"target,omitempty"`
+	Internal bool   `json:"internal"`
+	Count    int    `json:"count"`
+}
+
+type LinkOptions struct {
+	Include []string
+	Exclude []string
+	Scope   string
+	Limit   int
+}
+
+const collectLinksJS = `() => {
+  const norm = (t) => (t || "").replace(/\s+/g, " ").trim();
+  const out = [];
+  for (const a of document.querySelectorAll("a[href], area[href]")) {
+    let href = "";
+    try {
+      href = String(a.href || "");
+    } catch {
+      continue;
+    }
+    if (!href) continue;
+    const text = norm(a.innerText || a.textContent || "") ||
+      norm(a.getAttribute("aria-label")) ||
+      norm(a.getAttribute("title")) ||
+      norm(a.getAttribute("alt"));
+    out.push({
+      href,
+      text,
+      rel: a.getAttribute("rel") || "",
+      target: a.getAttribute("target") || "",
+    });
+  }
+  return out;
+}`
+
+func CollectLinks(page playwright.Page, opts LinkOptions) ([]LinkInfo, int, error) {
+	raw, err := page.Evaluate(collectLinksJS)
+	if err != nil {
+		return nil, 0, err
+	}
+	arr, ok := raw.([]interface{})
+	if !ok {
+		return nil, 0, fmt.Errorf("unexpected links result")
+	}
+	found := make([]LinkInfo, 0, len(arr))
+	for _, entry := range arr {
+		m, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		link := LinkInfo{}
+		link.Href, _ = m["href"].(string)
+		link.Text, _ = m["text"].(string)
+		link.Rel, _ = m["rel"].(string)
+		link.Target, _ = m["target"].(string)
+		found = append(found, link)
+	}
+	return filterLinks(page.URL(), found, opts)
+}
+
+// filterLinks classifies, deduplicates (by href, fragment included) and
+// filters raw anchors. It returns the selected links and the number of unique
+// links before include/exclude/scope/limit were applied.
+func filterLinks(pageURL string, links []LinkInfo, opts LinkOptions) ([]LinkInfo, int, error) {
+	include, err := compileLinkPatterns(opts.Include)
+	if err != nil {
+		return nil, 0, err
+	}
+	exclude, err := compileLinkPatterns(opts.Exclude)
+	if err != nil {
+		return nil, 0, err
+	}
+	scope := strings.ToLower(strings.TrimSpace(opts.Scope))
+	if scope == "" {
+		scope = "all"
+	}
+	if scope != "all" && scope != "internal" && scope != "external" {
+		return nil, 0, invalidArgf("invalid scope '%s' (expected all, internal, external)", opts.Scope)
+	}
+	base, _ := url.Parse(pageURL)
+
+	unique := []LinkInfo{}
+	index := map[string]int{}
+	for _, link := range links {
+		if i, ok := index[link.Href]; ok {
+			unique[i].Count++
+			if unique[i].Text == "" {
+				unique[i].Text = link.Text
+			}
+			continue
+		}
+		link.Count = 1
+		link.Internal = isInternalLink(base, link.Href)
+		index[link.Href] = len(unique)
+		unique = append(unique, link)
+	}
+
+	out := []LinkInfo{}
+	for _, link := range unique {
+		if scope == "internal" && !link.Internal {
+			continue
+		}
+		if scope == "external" && link.Internal {
+			continue
+		}
+		if len(include) > 0 && !matchAnyLinkPattern(include, link.Href) {
+			continue
+		}
+		if matchAnyLinkPattern(exclude, link.Href) {
+			continue
+		}
+		out = append(out, link)
+		if opts.Limit > 0 && len(out) >= opts.Limit {
+			break
+		}
+	}
+	return out, len(unique), nil
+}
+
+func isInternalLink(base *url.URL, href string) bool {
+	u, err := url.Parse(href)
+	if err != nil || base == nil {
+		return false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	return strings.EqualFold(u.Hostname(), base.Hostname())
+}
+
+type linkPattern struct {
+	re       *regexp.Regexp
+	absolute bool
+}
+
+// compileLinkPatterns turns globs into matchers. "*" matches any run of
+// characters. Patterns containing "://" match the absolute href; others match
+// the path (plus query), so "/docs/*" selects every docs link on any host.
+func compileLinkPatterns(patterns []string) ([]linkPattern, error) {
+	out := []linkPattern{}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		var sb strings.Builder
+		sb.WriteString("^")
+		for _, r := range p {
+			switch r {
+			case '*':
+				sb.WriteString(".*")
+			case '?':
+				sb.WriteString(".")
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		sb.WriteString("$")
+		re, err := regexp.Compile(sb.String())
+		if err != nil {
+			return nil, invalidArgf("invalid link pattern '%s': %v", p, err)
+		}
+		out = append(out, linkPattern{re: re, absolute: strings.Contains(p, "://")})
+	}
+	return out, nil
+}
+
+func matchAnyLinkPattern(patterns []linkPattern, href string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	subject := href
+	if u, err := url.Parse(href); err == nil {
+		subject = u.EscapedPath()
+		if subject == "" {
+			subject = "/"
+		}
+		if u.RawQuery != "" {
+			subject += "?" + u.RawQuery
+		}
+	}
+	for _, p := range patterns {
+		if p.absolute {
+			if p.re.MatchString(href) {
+				return true
+			}
+			continue
+		}
+		if p.re.MatchString(subject) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package devbrowser
+
+import "testing"
+
+func TestFilterLinks_DedupeAndScope(t *testing.T) {
+	links := []LinkInfo{
+		{Href: "https://example.com/docs/intro", Text: "Intro"},
+		{Href: "https://example.com/docs/intro", Text: "Intro again"},
+		{Href: "https://example.com/pricing", Text: "Pricing"},
+		{Href: "https://other.org/docs/x", Text: "Other"},
+		{Href: "mailto:hi@example.com", Text: "Mail"},
+	}
+	out, total, err := filterLinks("https://example.com/", links, LinkOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 4 || len(out) != 4 {
+		t.Fatalf("expected 4 unique links, got total=%d len=%d", total, len(out))
+	}
+	if out[0].Count != 2 || out[0].Text != "Intro" {
+		t.Fatalf("unexpected dedupe result: %+v", out[0])
+	}
+	if !out[0].Internal || out[2].Internal || out[3].Internal {
+		t.Fatalf("unexpected internal classification: %+v", out)
+	}
+
+	internal, _, err := filterLinks("https://example.com/", links, LinkOptions{Scope: "internal"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(internal) != 2 {
+		t.Fatalf("expected 2 internal links, got %+v", internal)
+	}
+}
+
+func TestFilterLinks_Patterns(t *testing.T) {
+	links := []LinkInfo{
+		{Href: "https://example.com/docs/intro"},
+		{Href: "https://example.com/docs/api?v=2"},
+		{Href: "https://example.com/blog/post"},
+		{Href: "https://other.org/docs/x"},
+	}
+	out, _, err := filterLinks("https://example.com/", links, LinkOptions{Include: []string{"/docs/*"}, Exclude: []string{"*api*"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].Href != links[0].Href || out[1].Href != links[3].Href {
+		t.Fatalf("unexpected filtered links: %+v", out)
+	}
+
+	out, _, err = filterLinks("https://example.com/", links, LinkOptions{Include: []string{"https://example.com/*"}, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 {
+		t.Fatalf("expected limit to apply, got %+v", out)
+	}
+}
+
+func TestFilterLinks_InvalidScope(t *testing.T) {
+	if _, _, err := filterLinks("https://example.com/", nil, LinkOptions{Scope: "nearby"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
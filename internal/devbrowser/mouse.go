@@ -0,0 +1,153 @@
+package devbrowser
+
+import (
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// runMouse handles click_xy, mouse_move, mouse_down, mouse_up and
+// mouse_wheel. Coordinates are CSS pixels in the viewport; when a target is
+// given they are offsets from its bounding box's top-left corner and default
+// to its center. Without either, mouse_down, mouse_up and mouse_wheel act
+// where the pointer already is.
+func runMouse(page playwright.Page, name string, args map[string]interface{}) (RunResult, error) {
+	spec, err := optionalTargetSpec(args, 5_000)
+	if err != nil {
+		return nil, err
+	}
+	x, hasX, err := optionalFloat(args, "x")
+	if err != nil {
+		return nil, err
+	}
+	y, hasY, err := optionalFloat(args, "y")
+	if err != nil {
+		return nil, err
+	}
+	if hasX != hasY {
+		return nil, invalidArgf("x and y must be given together")
+	}
+	button, err := optionalString(args, "button", "left")
+	if err != nil {
+		return nil, err
+	}
+	mouseButton, err := parseMouseButton(button)
+	if err != nil {
+		return nil, err
+	}
+
+	res := RunResult{"action": name}
+	hasPoint := hasX
+	if spec.isSet() {
+		box, err := resolveBounds(page, spec)
+		if err != nil {
+			return nil, err
+		}
+		x, y = resolveMousePoint(box, x, y, hasX)
+		hasPoint = true
+		res["target"] = spec.describe()
+		res["box"] = map[string]float64{"x": box.X, "y": box.Y, "width": box.Width, "height": box.Height}
+	}
+	if hasPoint {
+		res["x"] = x
+		res["y"] = y
+	}
+
+	mouse := page.Mouse()
+	switch name {
+	case "click_xy":
+		if !hasPoint {
+			return nil, invalidArgf("click_xy requires x/y or a target")
+		}
+		clicks, err := optionalInt(args, "click_count", 1)
+		if err != nil {
+			return nil, err
+		}
+		delay, err := optionalInt(args, "delay_ms", 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := mouse.Click(x, y, playwright.MouseClickOptions{
+			Button:     mouseButton,
+			ClickCount: playwright.Int(clicks),
+			Delay:      playwright.Float(float64(delay)),
+		}); err != nil {
+			return nil, err
+		}
+		res["button"] = button
+		res["clicked"] = true
+
+	case "mouse_move":
+		if !hasPoint {
+			return nil, invalidArgf("mouse_move requires x/y or a target")
+		}
+		steps, err := optionalInt(args, "steps", 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := mouse.Move(x, y, playwright.MouseMoveOptions{Steps: playwright.Int(steps)}); err != nil {
+			return nil, err
+		}
+		res["moved"] = true
+
+	case "mouse_down", "mouse_up":
+		if hasPoint {
+			if err := mouse.Move(x, y); err != nil {
+				return nil, err
+			}
+		}
+		if name == "mouse_down" {
+			err = mouse.Down(playwright.MouseDownOptions{Button: mouseButton})
+		} else {
+			err = mouse.Up(playwright.MouseUpOptions{Button: mouseButton})
+		}
+		if err != nil {
+			return nil, err
+		}
+		res["button"] = button
+
+	case "mouse_wheel":
+		dx, _, err := optionalFloat(args, "delta_x")
+		if err != nil {
+			return nil, err
+		}
+		dy, _, err := optionalFloat(args, "delta_y")
+		if err != nil {
+			return nil, err
+		}
+		if hasPoint {
+			if err := mouse.Move(x, y); err != nil {
+				return nil, err
+			}
+		}
+		if err := mouse.Wheel(dx, dy); err != nil {
+			return nil, err
+		}
+		res["delta_x"] = dx
+		res["delta_y"] = dy
+
+	default:
+		return nil, invalidArgf("unknown mouse action '%s'", name)
+	}
+	return res, nil
+}
+
+func resolveMousePoint(box *playwright.Rect, offsetX, offsetY float64, hasOffset bool) (float64, float64) {
+	if !hasOffset {
+		return box.X + box.Width/2, box.Y + box.Height/2
+	}
+	return box.X + offsetX, box.Y + offsetY
+}
+
+func parseMouseButton(value string) (*playwright.MouseButton, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "left":
+		return playwright.MouseButtonLeft, nil
+	case "right":
+		return playwright.MouseButtonRight, nil
+	case "middle":
+		return playwright.MouseButtonMiddle, nil
+	default:
+		return nil, invalidArgf("invalid button '%s' (expected left, right, middle)", value)
+	}
+}
@@ -0,0 +1,39 @@
+package devbrowser
+
+import (
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+func TestResolveMousePoint(t *testing.T) {
+	box := &playwright.Rect{X: 100, Y: 50, Width: 200, Height: 80}
+	if x, y := resolveMousePoint(box, 0, 0, false); x != 200 || y != 90 {
+		t.Fatalf("expected center (200,90), got (%v,%v)", x, y)
+	}
+	if x, y := resolveMousePoint(box, 10, -5, true); x != 110 || y != 45 {
+		t.Fatalf("expected offset (110,45), got (%v,%v)", x, y)
+	}
+}
+
+func TestParseMouseButton(t *testing.T) {
+	if b, err := parseMouseButton("Right"); err != nil || *b != *playwright.MouseButtonRight {
+		t.Fatalf("expected right button, got %v (%v)", b, err)
+	}
+	if _, err := parseMouseButton("side"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestOptionalFloat(t *testing.T) {
+	v, ok, err := optionalFloat(map[string]interface{}{"x": -12.5}, "x")
+	if err != nil || !ok || v != -12.5 {
+		t.Fatalf("unexpected result %v %v %v", v, ok, err)
+	}
+	if _, ok, err := optionalFloat(map[string]interface{}{}, "x"); err != nil || ok {
+		t.Fatalf("expected missing key to be absent")
+	}
+	if _, _, err := optionalFloat(map[string]interface{}{"x": true}, "x"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
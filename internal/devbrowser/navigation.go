@@ -0,0 +1,305 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// NavigationError is a failed or rejected navigation. Class is one of dns,
+// tls, connection, timeout, http_4xx, http_5xx, http_status or
+// navigation_failed.
+type NavigationError struct {
+	Class  string
+	URL    string
+	Status int
+	Err    error
+}
+
+func (e *NavigationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("navigation failed [%s]: %v", e.Class, e.Err)
+	}
+	return fmt.Sprintf("navigation failed [%s]: %s returned %d", e.Class, e.URL, e.Status)
+}
+
+func (e *NavigationError) Unwrap() error {
+	return e.Err
+}
+
+// reportedResponseHeaders are the response headers worth showing an agent;
+// cookies and other noisy or sensitive headers are left out.
+var reportedResponseHeaders = map[string]bool{
+	"cache-control":    true,
+	"content-language": true,
+	"content-length":   true,
+	"content-type":     true,
+	"etag":             true,
+	"last-modified":    true,
+	"location":         true,
+	"retry-after":      true,
+	"server":           true,
+	"www-authenticate": true,
+	"x-request-id":     true,
+}
+
+func runGoto(page playwright.Page, args map[string]interface{}) (RunResult, error) {
+	url, err := requireString(args, "url")
+	if err != nil {
+		return nil, err
+	}
+	waitUntil, err := optionalString(args, "wait_until", "domcontentloaded")
+	if err != nil {
+		return nil, err
+	}
+	timeoutMs, err := optionalInt(args, "timeout_ms", 45_000)
+	if err != nil {
+		return nil, err
+	}
+	var expect *statusExpectation
+	if raw, ok := args["expect_status"]; ok && raw != nil {
+		if expect, err = parseStatusExpectation(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	resp, err := page.Goto(url, playwright.PageGotoOptions{
+		WaitUntil: getWaitUntil(waitUntil),
+		Timeout:   playwright.Float(float64(timeoutMs)),
+	})
+	if err != nil {
+		return nil, &NavigationError{Class: classifyNavigationError(err), URL: url, Err: err}
+	}
+
+	res := RunResult{"url": page.URL(), "title": safeTitle(page)}
+	timing := map[string]interface{}{"total_ms": time.Since(start).Milliseconds()}
+	res["timing"] = timing
+	if resp == nil {
+		// Same-document navigations (hash changes) have no response.
+		return res, nil
+	}
+
+	status := resp.Status()
+	res["status"] = status
+	res["status_text"] = resp.StatusText()
+	res["redirects"] = redirectChain(resp.Request())
+	if headers, err := resp.AllHeaders(); err == nil {
+		res["headers"] = filterResponseHeaders(headers)
+	}
+	for k, v := range requestTiming(resp.Request().Timing()) {
+		timing[k] = v
+	}
+
+	if expect != nil && !expect.matches(status) {
+		return nil, &NavigationError{Class: classifyStatus(status), URL: page.URL(), Status: status, Err: fmt.Errorf("%s returned %d, expected %s", page.URL(), status, expect)}
+	}
+	return res, nil
+}
+
+func redirectChain(req playwright.Request) []map[string]interface{} {
+	chain := []map[string]interface{}{}
+	for r := req.RedirectedFrom(); r != nil; r = r.RedirectedFrom() {
+		hop := map[string]interface{}{"url": r.URL()}
+		if resp, err := r.Response(); err == nil && resp != nil {
+			hop["status"] = resp.Status()
+		}
+		chain = append([]map[string]interface{}{hop}, chain...)
+	}
+	return chain
+}
+
+func filterResponseHeaders(headers map[string]string) map[string]string {
+	out := map[string]string{}
+	for k, v := range headers {
+		if reportedResponseHeaders[strings.ToLower(k)] {
+			out[strings.ToLower(k)] = v
+		}
+	}
+	return out
+}
+
+func requestTiming(t *playwright.RequestTiming) map[string]interface{} {
+	out := map[string]interface{}{}
+	if t == nil {
+		return out
+	}
+	span := func(key string, from, to float64) {
+		if from >= 0 && to >= 0 && to >= from {
+			out[key] = int64(to - from)
+		}
+	}
+	span("dns_ms", t.DomainLookupStart, t.DomainLookupEnd)
+	span("connect_ms", t.ConnectStart, t.ConnectEnd)
+	span("tls_ms", t.SecureConnectionStart, t.ConnectEnd)
+	span("ttfb_ms", t.RequestStart, t.ResponseStart)
+	span("response_ms", t.ResponseStart, t.ResponseEnd)
+	return out
+}
+
+func classifyNavigationError(err error) string {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "err_name_not_resolved"), strings.Contains(msg, "err_name_resolution_failed"):
+		return "dns"
+	case strings.Contains(msg, "err_cert"), strings.Contains(msg, "err_ssl"), strings.Contains(msg, "ssl_protocol"):
+		return "tls"
+	case isTimeout(err), strings.Contains(msg, "err_timed_out"):
+		return "timeout"
+	case strings.Contains(msg, "err_connection"), strings.Contains(msg, "err_address_unreachable"), strings.Contains(msg, "err_internet_disconnected"):
+		return "connection"
+	default:
+		return "navigation_failed"
+	}
+}
+
+func classifyStatus(status int) string {
+	switch {
+	case status >= 400 && status < 500:
+		return "http_4xx"
+	case status >= 500 && status < 600:
+		return "http_5xx"
+	default:
+		return "http_status"
+	}
+}
+
+// statusExpectation is a set of inclusive status ranges, parsed from values
+// like 200, "2xx", "200-299", "200,304" or an array of those.
+type statusExpectation struct {
+	ranges [][2]int
+	raw    []string
+}
+
+func parseStatusExpectation(raw interface{}) (*statusExpectation, error) {
+	parts := []string{}
+	switch t := raw.(type) {
+	case string:
+		parts = strings.Split(t, ",")
+	case []interface{}:
+		for _, v := range t {
+			parts = append(parts, formValueString(v))
+		}
+	default:
+		if n, ok := asInt(raw); ok {
+			parts = []string{strconv.Itoa(n)}
+		} else {
+			return nil, invalidArgf("expect_status must be a status code, pattern (2xx, 200-299) or list")
+		}
+	}
+
+	exp := &statusExpectation{}
+	for _, part := range parts {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		var lo, hi int
+		var err error
+		switch {
+		case len(part) == 3 && strings.HasSuffix(part, "xx"):
+			d, convErr := strconv.Atoi(part[:1])
+			lo, hi, err = d*100, d*100+99, convErr
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			lo, err = strconv.Atoi(strings.TrimSpace(bounds[0]))
+			if err == nil {
+				hi, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			}
+		default:
+			lo, err = strconv.Atoi(part)
+			hi = lo
+		}
+		if err != nil || lo < 100 || hi > 599 || lo > hi {
+			return nil, invalidArgf("invalid expect_status '%s'", part)
+		}
+		exp.ranges = append(exp.ranges, [2]int{lo, hi})
+		exp.raw = append(exp.raw, part)
+	}
+	if len(exp.ranges) == 0 {
+		return nil, invalidArgf("expect_status is empty")
+	}
+	return exp, nil
+}
+
+func (e *statusExpectation) matches(status int) bool {
+	for _, r := range e.ranges {
+		if status >= r[0] && status <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *statusExpectation) String() string {
+	return strings.Join(e.raw, ",")
+}
+
+// runHistory handles back, forward and reload with the same wait_until and
+// timeout_ms handling as goto.
+func runHistory(page playwright.Page, name string, args map[string]interface{}) (RunResult, error) {
+	waitUntil, err := optionalString(args, "wait_until", "domcontentloaded")
+	if err != nil {
+		return nil, err
+	}
+	timeoutMs, err := optionalInt(args, "timeout_ms", 45_000)
+	if err != nil {
+		return nil, err
+	}
+	timeout := playwright.Float(float64(timeoutMs))
+	fromURL := page.URL()
+
+	var resp playwright.Response
+	switch name {
+	case "back":
+		resp, err = page.GoBack(playwright.PageGoBackOptions{WaitUntil: getWaitUntil(waitUntil), Timeout: timeout})
+	case "forward":
+		resp, err = page.GoForward(playwright.PageGoForwardOptions{WaitUntil: getWaitUntil(waitUntil), Timeout: timeout})
+	case "reload":
+		bypassCache, bErr := optionalBool(args, "bypass_cache", false)
+		if bErr != nil {
+			return nil, bErr
+		}
+		if bypassCache {
+			resp, err = reloadIgnoringCache(page, waitUntil, timeoutMs)
+		} else {
+			resp, err = page.Reload(playwright.PageReloadOptions{WaitUntil: getWaitUntil(waitUntil), Timeout: timeout})
+		}
+	default:
+		return nil, invalidArgf("unknown navigation '%s'", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := RunResult{"url": page.URL(), "title": safeTitle(page), "from_url": fromURL}
+	if name != "reload" {
+		// Playwright returns no response when there is no history entry to
+		// go to (or for same-document navigations).
+		res["navigated"] = resp != nil || page.URL() != fromURL
+	}
+	if resp != nil {
+		res["status"] = resp.Status()
+	}
+	return res, nil
+}
+
+// reloadIgnoringCache reloads through CDP, since Playwright's reload has no
+// cache bypass option.
+func reloadIgnoringCache(page playwright.Page, waitUntil string, timeoutMs int) (playwright.Response, error) {
+	session, err := page.Context().NewCDPSession(page)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Detach()
+	return page.ExpectNavigation(func() error {
+		_, err := session.Send("Page.reload", map[string]interface{}{"ignoreCache": true})
+		return err
+	}, playwright.PageExpectNavigationOptions{
+		WaitUntil: getWaitUntil(waitUntil),
+		Timeout:   playwright.Float(float64(timeoutMs)),
+	})
+}
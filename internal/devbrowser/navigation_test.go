@@ -0,0 +1,69 @@
+package devbrowser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+func TestParseStatusExpectation(t *testing.T) {
+	cases := []struct {
+		raw   interface{}
+		ok    []int
+		notOk []int
+	}{
+		{float64(200), []int{200}, []int{201, 404}},
+		{"2xx", []int{200, 204, 299}, []int{301, 404}},
+		{"200-299", []int{200, 250}, []int{300}},
+		{"200,304", []int{200, 304}, []int{301}},
+		{[]interface{}{"2xx", float64(404)}, []int{201, 404}, []int{500}},
+	}
+	for _, tc := range cases {
+		exp, err := parseStatusExpectation(tc.raw)
+		if err != nil {
+			t.Fatalf("parse %v: %v", tc.raw, err)
+		}
+		for _, code := range tc.ok {
+			if !exp.matches(code) {
+				t.Errorf("%v should match %d", tc.raw, code)
+			}
+		}
+		for _, code := range tc.notOk {
+			if exp.matches(code) {
+				t.Errorf("%v should not match %d", tc.raw, code)
+			}
+		}
+	}
+
+	for _, bad := range []interface{}{"abc", "9xx", "300-200", "", true} {
+		if _, err := parseStatusExpectation(bad); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
+
+func TestClassifyNavigationError(t *testing.T) {
+	cases := map[string]string{
+		"page.goto: net::ERR_NAME_NOT_RESOLVED at https://nope.invalid/": "dns",
+		"page.goto: net::ERR_CERT_AUTHORITY_INVALID at https://self/":    "tls",
+		"page.goto: net::ERR_TIMED_OUT at https://slow.test/":            "timeout",
+		"page.goto: net::ERR_CONNECTION_REFUSED at http://localhost:1/":  "connection",
+		"page.goto: net::ERR_ABORTED":                                    "navigation_failed",
+	}
+	for msg, want := range cases {
+		if got := classifyNavigationError(errors.New(msg)); got != want {
+			t.Errorf("%q: got %s, want %s", msg, got, want)
+		}
+	}
+	if got := classifyNavigationError(fmt.Errorf("%w: page.goto: Timeout 30000ms exceeded.", playwright.ErrTimeout)); got != "timeout" {
+		t.Errorf("playwright timeout: got %s", got)
+	}
+	if got := classifyStatus(404); got != "http_4xx" {
+		t.Errorf("404: got %s", got)
+	}
+	if got := classifyStatus(503); got != "http_5xx" {
+		t.Errorf("503: got %s", got)
+	}
+}
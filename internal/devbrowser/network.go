@@ -0,0 +1,330 @@
+package devbrowser
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const defaultNetworkRecentMax = 200
+
+// networkIgnore decides which URLs network and perf waits leave out. Hosts
+// match the domain and its subdomains, hostLabels the first label of the
+// host, and segments a whole path segment with or without an extension, so
+// first-party paths like /uploads/ or /api/events/ still count. Substrings
+// (from the caller's ignore list) match anywhere in the URL.
+type networkIgnore struct {
+	hosts      []string
+	hostLabels []string
+	segments   []string
+	substrings []string
+}
+
+// defaultNetworkIgnore covers analytics, ads and beacons that never settle
+// and should not hold up network waits.
+var defaultNetworkIgnore = networkIgnore{
+	hosts: []string{
+		"doubleclick.net",
+		"googlesyndication.com",
+		"googletagmanager.com",
+		"google-analytics.com",
+		"facebook.net",
+		"hotjar.com",
+		"clarity.ms",
+		"mixpanel.com",
+		"segment.com",
+		"segment.io",
+		"newrelic.com",
+		"nr-data.net",
+	},
+	hostLabels: []string{"analytics", "ads", "pixel", "tracking", "telemetry"},
+	segments:   []string{"tracker", "collector", "beacon", "telemetry", "pixel", "track"},
+}
+
+func ignoreSubstrings(patterns []string) networkIgnore {
+	return networkIgnore{substrings: patterns}
+}
+
+func (n networkIgnore) matches(rawURL string) bool {
+	for _, p := range n.substrings {
+		if p != "" && strings.Contains(rawURL, p) {
+			return true
+		}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range n.hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	label, _, _ := strings.Cut(host, ".")
+	for _, l := range n.hostLabels {
+		if label == l {
+			return true
+		}
+	}
+	for _, seg := range strings.Split(strings.ToLower(u.Path), "/") {
+		name, _, _ := strings.Cut(seg, ".")
+		for _, s := range n.segments {
+			if seg == s || name == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type NetworkRequest struct {
+	URL          string `json:"url"`
+	Method       string `json:"method,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	StartedMS    int64  `json:"started_ms"`
+	EndedMS      int64  `json:"ended_ms,omitempty"`
+	Status       int    `json:"status,omitempty"`
+	Failed       bool   `json:"failed,omitempty"`
+}
+
+type NetworkSocket struct {
+	URL         string `json:"url"`
+	OpenedMS    int64  `json:"opened_ms"`
+	LastFrameMS int64  `json:"last_frame_ms,omitempty"`
+}
+
+// NetworkActivity is the daemon's view of a page's traffic: requests still
+// in flight, recently finished ones and open WebSockets.
+type NetworkActivity struct {
+	NowMS    int64            `json:"now_ms"`
+	Inflight []NetworkRequest `json:"inflight"`
+	Recent   []NetworkRequest `json:"recent"`
+	Sockets  []NetworkSocket  `json:"websockets"`
+}
+
+type networkStore struct {
+	mu    sync.Mutex
+	pages map[string]*pageNetwork
+	max   int
+}
+
+type pageNetwork struct {
+	inflight map[playwright.Request]NetworkRequest
+	recent   []NetworkRequest
+	sockets  map[playwright.WebSocket]NetworkSocket
+}
+
+func newNetworkStore(max int) *networkStore {
+	if max <= 0 {
+		max = defaultNetworkRecentMax
+	}
+	return &networkStore{pages: make(map[string]*pageNetwork), max: max}
+}
+
+func (n *networkStore) pageLocked(name string) *pageNetwork {
+	p, ok := n.pages[name]
+	if !ok {
+		p = &pageNetwork{
+			inflight: make(map[playwright.Request]NetworkRequest),
+			sockets:  make(map[playwright.WebSocket]NetworkSocket),
+		}
+		n.pages[name] = p
+	}
+	return p
+}
+
+func (n *networkStore) started(name string, req playwright.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pageLocked(name).inflight[req] = NetworkRequest{
+		URL:          req.URL(),
+		Method:       req.Method(),
+		ResourceType: req.ResourceType(),
+		StartedMS:    NowMS(),
+	}
+}
+
+// responded records the status of a request still in flight.
+func (n *networkStore) responded(name string, req playwright.Request, status int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	p := n.pageLocked(name)
+	if entry, ok := p.inflight[req]; ok {
+		entry.Status = status
+		p.inflight[req] = entry
+	}
+}
+
+func (n *networkStore) ended(name string, req playwright.Request, failed bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	p := n.pageLocked(name)
+	entry, ok := p.inflight[req]
+	if !ok {
+		entry = NetworkRequest{URL: req.URL(), Method: req.Method(), ResourceType: req.ResourceType(), StartedMS: NowMS()}
+	}
+	delete(p.inflight, req)
+	entry.EndedMS = NowMS()
+	entry.Failed = failed
+	n.recordLocked(p, entry)
+}
+
+func (n *networkStore) recordLocked(p *pageNetwork, entry NetworkRequest) {
+	if len(p.recent) >= n.max {
+		p.recent = p.recent[len(p.recent)-n.max+1:]
+	}
+	p.recent = append(p.recent, entry)
+}
+
+func (n *networkStore) socketOpened(name string, ws playwright.WebSocket) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pageLocked(name).sockets[ws] = NetworkSocket{URL: ws.URL(), OpenedMS: NowMS()}
+}
+
+func (n *networkStore) socketFrame(name string, ws playwright.WebSocket) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	p := n.pageLocked(name)
+	if sock, ok := p.sockets[ws]; ok {
+		sock.LastFrameMS = NowMS()
+		p.sockets[ws] = sock
+	}
+}
+
+func (n *networkStore) socketClosed(name string, ws playwright.WebSocket) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	p := n.pageLocked(name)
+	sock, ok := p.sockets[ws]
+	if !ok {
+		return
+	}
+	delete(p.sockets, ws)
+	n.recordLocked(p, NetworkRequest{URL: sock.URL, ResourceType: "websocket", StartedMS: sock.OpenedMS, EndedMS: NowMS()})
+}
+
+func (n *networkStore) activity(name string) NetworkActivity {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	act := NetworkActivity{NowMS: NowMS(), Inflight: []NetworkRequest{}, Recent: []NetworkRequest{}, Sockets: []NetworkSocket{}}
+	p, ok := n.pages[name]
+	if !ok {
+		return act
+	}
+	for _, req := range p.inflight {
+		act.Inflight = append(act.Inflight, req)
+	}
+	act.Recent = append(act.Recent, p.recent...)
+	for _, sock := range p.sockets {
+		act.Sockets = append(act.Sockets, sock)
+	}
+	return act
+}
+
+func (n *networkStore) clear(name string) {
+	n.mu.Lock()
+	delete(n.pages, name)
+	n.mu.Unlock()
+}
+
+func (n *networkStore) clearAll() {
+	n.mu.Lock()
+	n.pages = make(map[string]*pageNetwork)
+	n.mu.Unlock()
+}
+
+type NetworkIdleOptions struct {
+	IdleMs       int
+	MaxRequestMs int
+	Ignore       networkIgnore
+}
+
+// networkIdleState decides whether act is idle: no relevant request in
+// flight and no relevant traffic for opts.IdleMs. Ignored URLs and requests
+// running longer than opts.MaxRequestMs (long-polls) do not count. It returns
+// the pending requests and how long the network has been quiet.
+func networkIdleState(act *NetworkActivity, opts NetworkIdleOptions) (bool, []NetworkRequest, int64) {
+	ignored := opts.Ignore.matches
+
+	last := int64(0)
+	pending := []NetworkRequest{}
+	for _, req := range act.Inflight {
+		if ignored(req.URL) {
+			continue
+		}
+		if opts.MaxRequestMs > 0 && act.NowMS-req.StartedMS > int64(opts.MaxRequestMs) {
+			continue
+		}
+		pending = append(pending, req)
+		last = max(last, req.StartedMS)
+	}
+	for _, req := range act.Recent {
+		if !ignored(req.URL) {
+			last = max(last, req.EndedMS)
+		}
+	}
+	for _, sock := range act.Sockets {
+		if !ignored(sock.URL) {
+			last = max(last, sock.OpenedMS, sock.LastFrameMS)
+		}
+	}
+
+	quiet := act.NowMS - last
+	if last == 0 {
+		quiet = int64(opts.IdleMs)
+	}
+	return len(pending) == 0 && quiet >= int64(opts.IdleMs), pending, quiet
+}
+
+func waitNetwork(page playwright.Page, events PageEvents, opts NetworkIdleOptions, timeoutMs int, minWaitMs int) (RunResult, error) {
+	if events == nil {
+		return nil, NewToolError(ErrDaemonUnavailable, errors.New("network strategy requires the daemon's request tracking"), nil)
+	}
+	start := time.Now()
+	if minWaitMs > 0 {
+		page.WaitForTimeout(float64(minWaitMs))
+	}
+	deadline := start.Add(time.Duration(timeoutMs) * time.Millisecond)
+
+	var pending []NetworkRequest
+	var quiet int64
+	idle := false
+	for {
+		act, err := events.NetworkActivity()
+		if err != nil {
+			return nil, err
+		}
+		idle, pending, quiet = networkIdleState(act, opts)
+		if idle || !time.Now().Before(deadline) {
+			break
+		}
+		page.WaitForTimeout(100)
+	}
+
+	res := RunResult{
+		"ok":               idle,
+		"strategy":         "network",
+		"timed_out":        !idle,
+		"waited_ms":        int(time.Since(start).Milliseconds()),
+		"idle_ms":          opts.IdleMs,
+		"quiet_ms":         quiet,
+		"pending_requests": len(pending),
+	}
+	if !idle && len(pending) > 0 {
+		urls := []string{}
+		for i, req := range pending {
+			if i == 10 {
+				break
+			}
+			urls = append(urls, req.URL)
+		}
+		res["pending"] = urls
+	}
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package devbrowser
+
+import "testing"
+
+func TestNetworkIdleState(t *testing.T) {
+	opts := NetworkIdleOptions{IdleMs: 500, MaxRequestMs: 10_000, Ignore: ignoreSubstrings([]string{"analytics"})}
+
+	act := &NetworkActivity{NowMS: 100_000}
+	if idle, _, _ := networkIdleState(act, opts); !idle {
+		t.Fatalf("no traffic should be idle")
+	}
+
+	act = &NetworkActivity{NowMS: 100_000, Inflight: []NetworkRequest{{URL: "https://app.test/api/items", StartedMS: 99_000}}}
+	idle, pending, _ := networkIdleState(act, opts)
+	if idle || len(pending) != 1 {
+		t.Fatalf("in-flight request should block idle, got idle=%v pending=%d", idle, len(pending))
+	}
+
+	act = &NetworkActivity{NowMS: 100_000, Inflight: []NetworkRequest{
+		{URL: "https://cdn.test/analytics.js", StartedMS: 99_900},
+		{URL: "https://app.test/poll", StartedMS: 80_000},
+	}}
+	if idle, pending, _ := networkIdleState(act, opts); !idle || len(pending) != 0 {
+		t.Fatalf("ignored and long-poll requests should not block, got idle=%v pending=%d", idle, len(pending))
+	}
+
+	act = &NetworkActivity{NowMS: 100_000, Recent: []NetworkRequest{{URL: "https://app.test/api", StartedMS: 99_500, EndedMS: 99_800}}}
+	idle, _, quiet := networkIdleState(act, opts)
+	if idle || quiet != 200 {
+		t.Fatalf("recent traffic inside idle window should block, got idle=%v quiet=%d", idle, quiet)
+	}
+
+	act = &NetworkActivity{NowMS: 100_000, Sockets: []NetworkSocket{{URL: "wss://app.test/live", OpenedMS: 90_000, LastFrameMS: 99_900}}}
+	if idle, _, _ := networkIdleState(act, opts); idle {
+		t.Fatalf("recent websocket frame should block idle")
+	}
+}
+
+func TestNetworkStoreRecentCap(t *testing.T) {
+	store := newNetworkStore(3)
+	p := store.pageLocked("main")
+	for i := 0; i < 5; i++ {
+		store.recordLocked(p, NetworkRequest{URL: "u", EndedMS: int64(i)})
+	}
+	act := store.activity("main")
+	if len(act.Recent) != 3 || act.Recent[0].EndedMS != 2 {
+		t.Fatalf("unexpected recent entries %+v", act.Recent)
+	}
+}
+
+func TestDefaultNetworkIgnore(t *testing.T) {
+	ignored := []string{
+		"https://www.google-analytics.com/g/collect?v=2",
+		"https://stats.g.doubleclick.net/j/collect",
+		"https://analytics.app.test/v1/batch",
+		"https://app.test/beacon/page",
+		"https://app.test/assets/pixel.gif?id=1",
+		"https://app.test/t/track.js",
+	}
+	for _, u := range ignored {
+		if !defaultNetworkIgnore.matches(u) {
+			t.Errorf("expected %s to be ignored", u)
+		}
+	}
+	kept := []string{
+		"https://app.test/uploads/photo.png",
+		"https://app.test/downloads/report.csv",
+		"https://app.test/threads/42",
+		"https://app.test/api/events/",
+		"https://app.test/log/in",
+		"https://app.test/api/tracking/42",
+		"https://roads.test/",
+		"https://notsegment.com/x",
+	}
+	for _, u := range kept {
+		if defaultNetworkIgnore.matches(u) {
+			t.Errorf("expected %s to count", u)
+		}
+	}
+	if !ignoreSubstrings([]string{"/poll"}).matches("https://app.test/api/poll?since=1") {
+		t.Error("caller patterns should match as substrings")
+	}
+}
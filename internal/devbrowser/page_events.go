@@ -0,0 +1,81 @@
+package devbrowser
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PageEvents exposes what the daemon has recorded for the page a call runs
+// against. RunCall executes in a short-lived CLI process, so only the daemon
+// has seen console messages and requests from before the call started.
+type PageEvents interface {
+	NetworkActivity() (*NetworkActivity, error)
+	// ConsoleSince returns entries with id > since at the given levels and
+	// the id to pass next time.
+	ConsoleSince(since int64, levels string, limit int) ([]ConsoleEntry, int64, error)
+}
+
+type daemonPageEvents struct {
+	profile string
+	page    string
+}
+
+// DaemonPageEvents reads page events for page from the profile's daemon.
+func DaemonPageEvents(profile string, page string) PageEvents {
+	return &daemonPageEvents{profile: profile, page: page}
+}
+
+func (d *daemonPageEvents) get(resource string, query url.Values) (map[string]any, error) {
+	base := DaemonBaseURL(d.profile)
+	if base == "" {
+		return nil, NewToolError(ErrDaemonUnavailable, errors.New("daemon not running"), nil)
+	}
+	endpoint := fmt.Sprintf("%s/pages/%s/%s", base, url.PathEscape(d.page), resource)
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	data, err := HTTPJSON("GET", endpoint, nil, 5*time.Second)
+	if err != nil {
+		return nil, NewToolError(ErrDaemonUnavailable, err, nil)
+	}
+	if ok, _ := data["ok"].(bool); !ok {
+		return nil, fmt.Errorf("%s failed: %v", resource, data["error"])
+	}
+	return data, nil
+}
+
+func (d *daemonPageEvents) NetworkActivity() (*NetworkActivity, error) {
+	data, err := d.get("network", nil)
+	if err != nil {
+		return nil, err
+	}
+	act := &NetworkActivity{}
+	if err := decodeJSResult(data, act); err != nil {
+		return nil, err
+	}
+	return act, nil
+}
+
+func (d *daemonPageEvents) ConsoleSince(since int64, levels string, limit int) ([]ConsoleEntry, int64, error) {
+	query := url.Values{}
+	query.Set("levels", levels)
+	query.Set("limit", strconv.Itoa(limit))
+	if since > 0 {
+		query.Set("since", strconv.FormatInt(since, 10))
+	}
+	data, err := d.get("console", query)
+	if err != nil {
+		return nil, 0, err
+	}
+	var out struct {
+		LastID int64          `json:"last_id"`
+		Logs   []ConsoleEntry `json:"logs"`
+	}
+	if err := decodeJSResult(data, &out); err != nil {
+		return nil, 0, err
+	}
+	return out.Logs, out.LastID, nil
+}
@@ -2,7 +2,6 @@ package devbrowser
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -97,7 +96,7 @@ func SafeArtifactPath(artifactDir, pathArg, defaultName string) (string, error)
 	}
 	allowed := artifactResolved == resolved || strings.HasPrefix(resolved, artifactResolved+string(os.PathSeparator))
 	if !allowed {
-		return "", fmt.Errorf("Refusing to write outside artifact dir: %s (allowed under %s). Set DEV_BROWSER_ALLOW_UNSAFE_PATHS=1 to override.", resolved, artifactResolved)
+		return "", invalidArgf("Refusing to write outside artifact dir: %s (allowed under %s). Set DEV_BROWSER_ALLOW_UNSAFE_PATHS=1 to override.", resolved, artifactResolved)
 	}
 
 	if err := os.MkdirAll(filepath.Dir(resolved), 0o755); err != nil {
@@ -0,0 +1,314 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+	"gopkg.in/yaml.v3"
+)
+
+// navigationGraceMs is how soon after a user action a navigation counts as
+// its consequence rather than a typed URL.
+const navigationGraceMs = 1500
+
+// recorderJS reports trusted clicks, field changes and Enter/Escape presses
+// to __devBrowser_recordEvent, with targets from __devBrowser_targetOf.
+const recorderJS = `(() => {
+  if (globalThis.__devBrowser_recorderInstalled) return;
+  globalThis.__devBrowser_recorderInstalled = true;
+
+  const send = (ev) => {
+    const fn = globalThis.__devBrowser_recordEvent;
+    if (!fn) return;
+    try {
+      Promise.resolve(fn(ev)).catch(() => {});
+    } catch {
+      // recorder detached
+    }
+  };
+  const target = (el) => globalThis.__devBrowser_targetOf
+    ? globalThis.__devBrowser_targetOf(el)
+    : { selector: el.tagName.toLowerCase() };
+  const actionable = (el) => el.closest("a[href], button, input, select, textarea, summary, label, [role], [contenteditable], [onclick], [tabindex]") || el;
+  const nonText = ["checkbox", "radio", "button", "submit", "reset", "image", "file", "range", "color"];
+  const isField = (el) => el.matches("textarea, select") || el.isContentEditable ||
+    (el.matches("input") && !nonText.includes((el.type || "").toLowerCase()));
+  const fieldValue = (el) => (el.isContentEditable ? el.innerText : el.value);
+  const isSecret = (el) => (el.type || "").toLowerCase() === "password";
+
+  document.addEventListener("click", (e) => {
+    if (!e.isTrusted || !(e.target instanceof Element)) return;
+    const el = actionable(e.target);
+    if (el.matches("select, option")) return;
+    send({ kind: "click", target: target(el) });
+  }, true);
+
+  document.addEventListener("change", (e) => {
+    if (!e.isTrusted || !(e.target instanceof Element)) return;
+    const el = e.target;
+    if (el.matches("select")) {
+      const opt = el.selectedOptions && el.selectedOptions[0];
+      send({ kind: "select", target: target(el), value: opt ? opt.value : el.value });
+    } else if (isField(el)) {
+      send({ kind: "fill", target: target(el), value: fieldValue(el), secret: isSecret(el) });
+    }
+  }, true);
+
+  document.addEventListener("focusout", (e) => {
+    if (!e.isTrusted || !(e.target instanceof Element) || !e.target.isContentEditable) return;
+    send({ kind: "fill", target: target(e.target), value: fieldValue(e.target) });
+  }, true);
+
+  document.addEventListener("keydown", (e) => {
+    if (!e.isTrusted || (e.key !== "Enter" && e.key !== "Escape")) return;
+    const el = e.target instanceof Element ? e.target : null;
+    // Enter submits before change fires, so flush the field first.
+    if (e.key === "Enter" && el && isField(el) && !el.matches("textarea, select")) {
+      send({ kind: "fill", target: target(el), value: fieldValue(el), secret: isSecret(el) });
+    }
+    send({ kind: "press", key: e.key });
+  }, true);
+})();`
+
+// RecordedEvent is one user interaction seen by the recorder.
+type RecordedEvent struct {
+	Kind   string                 `json:"kind"`
+	Target map[string]interface{} `json:"target,omitempty"`
+	Value  string                 `json:"value,omitempty"`
+	Key    string                 `json:"key,omitempty"`
+	URL    string                 `json:"url,omitempty"`
+	Secret bool                   `json:"secret,omitempty"`
+	TimeMS int64                  `json:"time_ms"`
+}
+
+// Record captures interactions on page until stop is closed or the page
+// closes. The listener is installed for the current document and every
+// navigation after it.
+func Record(page playwright.Page, stop <-chan struct{}) ([]RecordedEvent, error) {
+	var mu sync.Mutex
+	var settling sync.WaitGroup
+	events := []RecordedEvent{}
+	add := func(ev RecordedEvent) int {
+		ev.TimeMS = NowMS()
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev)
+		return len(events) - 1
+	}
+
+	if url := page.URL(); url != "" && url != "about:blank" {
+		add(RecordedEvent{Kind: "navigate", URL: url})
+	}
+	if err := page.ExposeFunction("__devBrowser_recordEvent", func(args ...interface{}) interface{} {
+		if len(args) == 0 {
+			return nil
+		}
+		var ev RecordedEvent
+		if err := decodeJSResult(args[0], &ev); err != nil || ev.Kind == "" {
+			return nil
+		}
+		settling.Add(1)
+		defer settling.Done()
+		// The event keeps its place and time; only its target is settled
+		// once Playwright has been asked about it.
+		i := add(ev)
+		if target := settleRecordedTarget(page, ev.Target); target != nil {
+			mu.Lock()
+			events[i].Target = target
+			mu.Unlock()
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	script := baseScript() + "\n" + recorderJS
+	if err := page.AddInitScript(playwright.Script{Content: &script}); err != nil {
+		return nil, err
+	}
+	if _, err := page.Evaluate(script); err != nil {
+		return nil, err
+	}
+	page.OnFrameNavigated(func(frame playwright.Frame) {
+		if frame.ParentFrame() == nil {
+			add(RecordedEvent{Kind: "navigate", URL: frame.URL()})
+		}
+	})
+	closed := make(chan struct{})
+	var closeOnce sync.Once
+	page.OnClose(func(playwright.Page) {
+		closeOnce.Do(func() { close(closed) })
+	})
+
+	select {
+	case <-stop:
+	case <-closed:
+	}
+
+	settling.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]RecordedEvent{}, events...), nil
+}
+
+// settleRecordedTarget checks a role target from __devBrowser_targetOf
+// against Playwright, whose accessible names the recorder only
+// approximates. It returns nil for other targets.
+func settleRecordedTarget(page playwright.Page, target map[string]interface{}) map[string]interface{} {
+	ref, _ := target["check_ref"].(string)
+	if ref == "" {
+		return nil
+	}
+	role, _ := target["aria_role"].(string)
+	name, _ := target["aria_name"].(string)
+	index, err := roleRefPosition(page, role, name, ref)
+	if err != nil {
+		index = -1
+	}
+	return settleRoleTarget(target, index)
+}
+
+// settleRoleTarget is the role target with nth set from the element's
+// index among Playwright's matches, or its CSS fallback when Playwright
+// does not match the element (index -1).
+func settleRoleTarget(target map[string]interface{}, index int) map[string]interface{} {
+	if index < 0 {
+		fallback, _ := target["fallback"].(string)
+		return map[string]interface{}{"selector": fallback}
+	}
+	out := map[string]interface{}{}
+	for key, value := range target {
+		if key != "check_ref" && key != "fallback" {
+			out[key] = value
+		}
+	}
+	out["nth"] = index + 1
+	return out
+}
+
+// RecordedSteps turns recorded events into actions steps. Elements are
+// located with a find step and used through its ref; navigations right after
+// a user action, clicks that only focus a field and repeated field values
+// are dropped. Password values become ${vars.passwordN} placeholders,
+// returned in vars.
+func RecordedSteps(events []RecordedEvent) ([]map[string]interface{}, map[string]interface{}) {
+	steps := []map[string]interface{}{}
+	vars := map[string]interface{}{}
+	lastAction := int64(0)
+	lastURL := ""
+	lastValues := map[string]string{}
+	targets := 0
+
+	find := func(target map[string]interface{}) string {
+		targets++
+		id := fmt.Sprintf("t%d", targets)
+		args := map[string]interface{}{}
+		for k, v := range target {
+			args[k] = v
+		}
+		if nth, ok := asInt(args["nth"]); ok && nth <= 1 {
+			delete(args, "nth")
+		}
+		// nth counts exact name matches, so the lookup must be exact too.
+		if _, ok := args["aria_name"]; ok {
+			args["exact"] = true
+		}
+		steps = append(steps, map[string]interface{}{"id": id, "name": "find", "arguments": args})
+		return fmt.Sprintf("${steps.%s.result.ref}", id)
+	}
+
+	for i, ev := range events {
+		switch ev.Kind {
+		case "navigate":
+			if ev.URL == lastURL || (lastAction > 0 && ev.TimeMS-lastAction <= navigationGraceMs) {
+				lastURL = ev.URL
+				continue
+			}
+			lastURL = ev.URL
+			steps = append(steps, map[string]interface{}{"name": "goto", "arguments": map[string]interface{}{"url": ev.URL}})
+			continue
+		case "click":
+			if i+1 < len(events) {
+				next := events[i+1]
+				if (next.Kind == "fill" || next.Kind == "select") && targetKey(next.Target) == targetKey(ev.Target) {
+					continue
+				}
+			}
+			ref := find(ev.Target)
+			steps = append(steps, map[string]interface{}{"name": "click_ref", "arguments": map[string]interface{}{"ref": ref}})
+		case "fill", "select":
+			key := targetKey(ev.Target)
+			if prev, ok := lastValues[key]; ok && prev == ev.Value {
+				continue
+			}
+			lastValues[key] = ev.Value
+			text := ev.Value
+			if ev.Secret {
+				name := fmt.Sprintf("password%d", len(vars)+1)
+				if len(vars) == 0 {
+					name = "password"
+				}
+				vars[name] = ""
+				text = fmt.Sprintf("${vars.%s}", name)
+			}
+			ref := find(ev.Target)
+			steps = append(steps, map[string]interface{}{"name": "fill_ref", "arguments": map[string]interface{}{"ref": ref, "text": text}})
+		case "press":
+			steps = append(steps, map[string]interface{}{"name": "press", "arguments": map[string]interface{}{"key": ev.Key}})
+		default:
+			continue
+		}
+		lastAction = ev.TimeMS
+	}
+	return steps, vars
+}
+
+func targetKey(target map[string]interface{}) string {
+	data, _ := json.Marshal(target)
+	return string(data)
+}
+
+// RecordedScenarioYAML renders steps as a one-scenario file for the run
+// command, using the tool-name step form.
+func RecordedScenarioYAML(name string, steps []map[string]interface{}, vars map[string]interface{}) ([]byte, error) {
+	stepNodes := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, step := range steps {
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if id, ok := step["id"].(string); ok {
+			appendYAMLPair(node, "id", id, 0)
+		}
+		tool, _ := step["name"].(string)
+		args, _ := step["arguments"].(map[string]interface{})
+		if key, ok := scenarioShorthand[tool]; ok && len(args) == 1 && args[key] != nil {
+			appendYAMLPair(node, tool, args[key], 0)
+		} else {
+			appendYAMLPair(node, tool, args, yaml.FlowStyle)
+		}
+		stepNodes.Content = append(stepNodes.Content, node)
+	}
+
+	scenario := &yaml.Node{Kind: yaml.MappingNode}
+	appendYAMLPair(scenario, "name", name, 0)
+	scenario.Content = append(scenario.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "steps"}, stepNodes)
+
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	appendYAMLPair(doc, "name", name, 0)
+	if len(vars) > 0 {
+		appendYAMLPair(doc, "vars", vars, 0)
+	}
+	doc.Content = append(doc.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: "scenarios"},
+		&yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{scenario}},
+	)
+	return yaml.Marshal(doc)
+}
+
+func appendYAMLPair(node *yaml.Node, key string, value interface{}, style yaml.Style) {
+	valueNode := &yaml.Node{}
+	if err := valueNode.Encode(value); err != nil {
+		valueNode = &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(value)}
+	}
+	valueNode.Style |= style
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, valueNode)
+}
@@ -0,0 +1,93 @@
+package devbrowser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecordedSteps(t *testing.T) {
+	email := map[string]interface{}{"aria_role": "textbox", "aria_name": "Email", "nth": float64(1)}
+	pass := map[string]interface{}{"selector": "#password"}
+	submit := map[string]interface{}{"aria_role": "button", "aria_name": "Sign in", "nth": float64(2)}
+	events := []RecordedEvent{
+		{Kind: "navigate", URL: "https://example.com/login", TimeMS: 0},
+		{Kind: "navigate", URL: "https://example.com/login", TimeMS: 10},
+		{Kind: "click", Target: email, TimeMS: 1000},
+		{Kind: "fill", Target: email, Value: "a@b.c", TimeMS: 2000},
+		{Kind: "fill", Target: email, Value: "a@b.c", TimeMS: 2100},
+		{Kind: "fill", Target: pass, Value: "hunter2", Secret: true, TimeMS: 3000},
+		{Kind: "click", Target: submit, TimeMS: 4000},
+		{Kind: "navigate", URL: "https://example.com/home", TimeMS: 4500},
+		{Kind: "press", Key: "Escape", TimeMS: 9000},
+		{Kind: "navigate", URL: "https://example.com/other", TimeMS: 20000},
+	}
+	steps, vars := RecordedSteps(events)
+
+	names := []string{}
+	for _, s := range steps {
+		names = append(names, s["name"].(string))
+	}
+	want := "goto find fill_ref find fill_ref find click_ref press goto"
+	if got := strings.Join(names, " "); got != want {
+		t.Fatalf("steps = %s, want %s", got, want)
+	}
+	if _, ok := steps[1]["arguments"].(map[string]interface{})["nth"]; ok {
+		t.Fatalf("nth 1 should be dropped: %v", steps[1])
+	}
+	if exact := steps[1]["arguments"].(map[string]interface{})["exact"]; exact != true {
+		t.Fatalf("role targets must be exact: %v", steps[1])
+	}
+	if _, ok := steps[3]["arguments"].(map[string]interface{})["exact"]; ok {
+		t.Fatalf("selector targets take no exact: %v", steps[3])
+	}
+	if nth := steps[5]["arguments"].(map[string]interface{})["nth"]; nth != float64(2) {
+		t.Fatalf("nth = %v, want 2", nth)
+	}
+	if ref := steps[2]["arguments"].(map[string]interface{})["ref"]; ref != "${steps.t1.result.ref}" {
+		t.Fatalf("ref = %v", ref)
+	}
+	if text := steps[4]["arguments"].(map[string]interface{})["text"]; text != "${vars.password}" {
+		t.Fatalf("secret text = %v", text)
+	}
+	if _, ok := vars["password"]; !ok || len(vars) != 1 {
+		t.Fatalf("vars = %v", vars)
+	}
+}
+
+func TestSettleRoleTarget(t *testing.T) {
+	target := map[string]interface{}{"aria_role": "button", "aria_name": "Save", "exact": true, "check_ref": "e3", "fallback": "#save"}
+	got := settleRoleTarget(target, 1)
+	want := map[string]interface{}{"aria_role": "button", "aria_name": "Save", "exact": true, "nth": 2}
+	if targetKey(got) != targetKey(want) {
+		t.Fatalf("settled = %v, want %v", got, want)
+	}
+	if got := settleRoleTarget(target, -1); targetKey(got) != targetKey(map[string]interface{}{"selector": "#save"}) {
+		t.Fatalf("unmatched role = %v, want the CSS fallback", got)
+	}
+}
+
+func TestRecordedScenarioYAML(t *testing.T) {
+	steps := []map[string]interface{}{
+		{"name": "goto", "arguments": map[string]interface{}{"url": "https://example.com"}},
+		{"id": "t1", "name": "find", "arguments": map[string]interface{}{"aria_role": "button", "aria_name": "Go"}},
+		{"name": "click_ref", "arguments": map[string]interface{}{"ref": "${steps.t1.result.ref}"}},
+	}
+	data, err := RecordedScenarioYAML("flow", steps, map[string]interface{}{"password": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{"goto: https://example.com", "id: t1", "find: {aria_name: Go, aria_role: button}", "vars:"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("yaml missing %q:\n%s", want, out)
+		}
+	}
+
+	file, err := ParseScenarioFile(data, "x")
+	if err != nil {
+		t.Fatalf("recorded yaml does not parse: %v\n%s", err, out)
+	}
+	if len(file.Scenarios) != 1 || len(file.Scenarios[0].Steps) != 3 {
+		t.Fatalf("scenarios = %+v", file.Scenarios)
+	}
+}
@@ -1,6 +1,7 @@
 package devbrowser
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -13,153 +14,188 @@ import (
 
 type RunResult map[string]interface{}
 
-type ActionsResult struct {
-	Results  []map[string]interface{}
-	Snapshot string
+// CallOptions is what a tool call needs besides the page and its arguments.
+type CallOptions struct {
+	// ArtifactDir receives screenshots, saved HTML and other files.
+	ArtifactDir string
+	// Events is the daemon's record of the page's console and network.
+	// Tools that need it fail with daemon_unavailable when it is nil.
+	Events PageEvents
+	// SessionLog is the page's export log. Calls are appended only while
+	// the file exists.
+	SessionLog string
 }
 
-func RunCall(page playwright.Page, name string, args map[string]interface{}, artifactDir string) (RunResult, error) {
+// RunCall runs one tool against page. Errors are returned as *ToolError.
+func RunCall(page playwright.Page, name string, args map[string]interface{}, opts CallOptions) (RunResult, error) {
+	var after *afterAction
+	if afterActionTools[name] {
+		parsed, err := parseAfterAction(args)
+		if err != nil {
+			return nil, ClassifyError(err)
+		}
+		after = parsed
+	}
+	logged := beginSessionCall(page, name, args, opts.SessionLog)
+	res, err := runCall(page, name, args, opts)
+	if err == nil && after != nil {
+		after.run(page, res, opts)
+	}
+	if err != nil {
+		return nil, ClassifyError(err)
+	}
+	logged.finish(page)
+	return res, nil
+}
+
+func runCall(page playwright.Page, name string, args map[string]interface{}, opts CallOptions) (RunResult, error) {
+	artifactDir := opts.ArtifactDir
 	switch name {
 	case "goto":
-		url, err := requireString(args, "url")
+		return runGoto(page, args)
+
+	case "back", "forward", "reload":
+		return runHistory(page, name, args)
+
+	case "snapshot":
+		return runSnapshot(page, args)
+
+	case "click_ref":
+		spec, err := actionTarget(args)
 		if err != nil {
 			return nil, err
 		}
-		waitUntil, err := optionalString(args, "wait_until", "domcontentloaded")
+		effects, err := startEffects(page, args, opts.Events)
 		if err != nil {
 			return nil, err
 		}
-		timeoutMs, err := optionalInt(args, "timeout_ms", 45_000)
+		defer effects.stop()
+		el, err := resolveElement(page, spec)
 		if err != nil {
 			return nil, err
 		}
-		_, err = page.Goto(url, playwright.PageGotoOptions{
-			WaitUntil: getWaitUntil(waitUntil),
-			Timeout:   playwright.Float(float64(timeoutMs)),
-		})
-		if err != nil {
-			return nil, err
+		defer el.Dispose()
+		if err := el.Click(playwright.ElementHandleClickOptions{Timeout: playwright.Float(float64(spec.timeoutMs()))}); err != nil {
+			return nil, clickFailure(el, spec, err)
+		}
+		res := spec.result()
+		res["clicked"] = true
+		if effects != nil {
+			res["effects"] = effects.finish()
 		}
-		return RunResult{"url": page.URL(), "title": safeTitle(page)}, nil
+		return res, nil
 
-	case "snapshot":
-		engine, err := optionalString(args, "engine", "simple")
+	case "fill_ref":
+		spec, err := actionTarget(args, "text")
 		if err != nil {
 			return nil, err
 		}
-		format, err := optionalString(args, "format", "list")
+		text, err := requireString(args, "text")
 		if err != nil {
 			return nil, err
 		}
-		interactiveOnly, err := optionalBool(args, "interactive_only", true)
+		strategy, err := optionalString(args, "strategy", "auto")
 		if err != nil {
 			return nil, err
 		}
-		includeHeadings, err := optionalBool(args, "include_headings", true)
+		effects, err := startEffects(page, args, opts.Events)
 		if err != nil {
 			return nil, err
 		}
-		maxItems, err := optionalInt(args, "max_items", 80)
+		defer effects.stop()
+		el, err := resolveElement(page, spec)
 		if err != nil {
 			return nil, err
 		}
-		maxChars, err := optionalInt(args, "max_chars", 8000)
+		fill, err := SmartFill(page, el, text, strategy, spec.timeoutMs())
+		_ = el.Dispose()
 		if err != nil {
 			return nil, err
 		}
-
-		snap, err := GetSnapshot(page, SnapshotOptions{
-			Engine:          engine,
-			Format:          format,
-			InteractiveOnly: interactiveOnly,
-			IncludeHeadings: includeHeadings,
-			MaxItems:        maxItems,
-			MaxChars:        maxChars,
-		})
-		if err != nil {
-			return nil, err
+		res := spec.result()
+		res["filled"] = true
+		res["kind"] = fill.Kind
+		res["strategy"] = fill.Strategy
+		if effects != nil {
+			res["effects"] = effects.finish()
 		}
-		return RunResult{
-			"url":      page.URL(),
-			"title":    safeTitle(page),
-			"engine":   engine,
-			"format":   format,
-			"snapshot": snap.Yaml,
-			"items":    snap.Items,
-		}, nil
+		return res, nil
 
-	case "click_ref":
-		ref, err := requireString(args, "ref")
+	case "press":
+		key, err := requireString(args, "key")
 		if err != nil {
 			return nil, err
 		}
-		timeoutMs, err := optionalInt(args, "timeout_ms", 15_000)
+		effects, err := startEffects(page, args, opts.Events)
 		if err != nil {
 			return nil, err
 		}
-		el, err := SelectRef(page, ref, "simple")
-		if err != nil {
+		defer effects.stop()
+		if err := page.Keyboard().Press(key); err != nil {
 			return nil, err
 		}
-		err = el.Click(playwright.ElementHandleClickOptions{Timeout: playwright.Float(float64(timeoutMs))})
-		_ = el.Dispose()
-		if err != nil {
-			return nil, err
+		res := RunResult{"key": key, "pressed": true}
+		if effects != nil {
+			res["effects"] = effects.finish()
 		}
-		return RunResult{"ref": ref, "clicked": true}, nil
+		return res, nil
 
-	case "fill_ref":
-		ref, err := requireString(args, "ref")
+	case "find":
+		return runFind(page, args)
+
+	case "expect":
+		checks, timeoutMs, err := parseExpectChecks(args)
 		if err != nil {
 			return nil, err
 		}
-		text, err := requireString(args, "text")
+		return runExpect(page, checks, timeoutMs, opts.Events)
+
+	case "wait":
+		cond, err := parseWaitCondition(args)
 		if err != nil {
 			return nil, err
 		}
-		timeoutMs, err := optionalInt(args, "timeout_ms", 15_000)
-		if err != nil {
-			return nil, err
+		if cond != nil {
+			return waitForCondition(page, cond, opts.Events)
 		}
-		el, err := SelectRef(page, ref, "simple")
+		strategy, err := optionalString(args, "strategy", "playwright")
 		if err != nil {
 			return nil, err
 		}
-		err = el.Fill(text, playwright.ElementHandleFillOptions{Timeout: playwright.Float(float64(timeoutMs))})
-		_ = el.Dispose()
+		state, err := optionalString(args, "state", "load")
 		if err != nil {
 			return nil, err
 		}
-		return RunResult{"ref": ref, "filled": true}, nil
-
-	case "press":
-		key, err := requireString(args, "key")
-		if err != nil {
-			return nil, err
+		allowedStates := map[string]bool{"load": true, "domcontentloaded": true, "networkidle": true, "commit": true}
+		if !allowedStates[strings.ToLower(state)] {
+			return nil, invalidArgf("invalid state '%s' (expected one of: load, domcontentloaded, networkidle, commit)", state)
 		}
-		if err := page.Keyboard().Press(key); err != nil {
+		timeoutMs, err := optionalInt(args, "timeout_ms", 10_000)
+		if err != nil {
 			return nil, err
 		}
-		return RunResult{"key": key, "pressed": true}, nil
-
-	case "wait":
-		strategy, err := optionalString(args, "strategy", "playwright")
+		minWaitMs, err := optionalInt(args, "min_wait_ms", 0)
 		if err != nil {
 			return nil, err
 		}
-		state, err := optionalString(args, "state", "load")
+
+		stableFrames, err := optionalInt(args, "stable_frames", 10)
 		if err != nil {
 			return nil, err
 		}
-		allowedStates := map[string]bool{"load": true, "domcontentloaded": true, "networkidle": true, "commit": true}
-		if !allowedStates[strings.ToLower(state)] {
-			return nil, fmt.Errorf("invalid state '%s' (expected one of: load, domcontentloaded, networkidle, commit)", state)
+		ignore := defaultNetworkIgnore
+		if _, ok := args["ignore"]; ok {
+			patterns, err := optionalStringList(args, "ignore")
+			if err != nil {
+				return nil, err
+			}
+			ignore = ignoreSubstrings(patterns)
 		}
-		timeoutMs, err := optionalInt(args, "timeout_ms", 10_000)
+		idleMs, err := optionalInt(args, "idle_ms", 500)
 		if err != nil {
 			return nil, err
 		}
-		minWaitMs, err := optionalInt(args, "min_wait_ms", 0)
+		maxRequestMs, err := optionalInt(args, "max_request_ms", 10_000)
 		if err != nil {
 			return nil, err
 		}
@@ -168,9 +204,13 @@ func RunCall(page playwright.Page, name string, args map[string]interface{}, art
 		case "playwright":
 			return waitPlaywright(page, state, timeoutMs, minWaitMs)
 		case "perf":
-			return waitPerf(page, state, timeoutMs, minWaitMs)
+			return waitPerf(page, state, ignore, timeoutMs, minWaitMs)
+		case "network":
+			return waitNetwork(page, opts.Events, NetworkIdleOptions{IdleMs: idleMs, MaxRequestMs: maxRequestMs, Ignore: ignore}, timeoutMs, minWaitMs)
+		case "stable":
+			return waitStable(page, stableFrames, timeoutMs, minWaitMs)
 		default:
-			return nil, fmt.Errorf("invalid strategy (expected 'playwright' or 'perf')")
+			return nil, invalidArgf("invalid strategy (expected 'playwright', 'perf', 'network' or 'stable')")
 		}
 
 	case "screenshot":
@@ -191,19 +231,7 @@ func RunCall(page playwright.Page, name string, args map[string]interface{}, art
 			return nil, err
 		}
 
-		selector, err := optionalString(args, "selector", "")
-		if err != nil {
-			return nil, err
-		}
-		ariaRole, err := optionalString(args, "aria_role", "")
-		if err != nil {
-			return nil, err
-		}
-		ariaName, err := optionalString(args, "aria_name", "")
-		if err != nil {
-			return nil, err
-		}
-		nth, err := optionalInt(args, "nth", 1)
+		spec, err := optionalTargetSpec(args, 5_000)
 		if err != nil {
 			return nil, err
 		}
@@ -211,14 +239,10 @@ func RunCall(page playwright.Page, name string, args map[string]interface{}, art
 		if err != nil {
 			return nil, err
 		}
-		targetTimeout, err := optionalInt(args, "timeout_ms", 5_000)
-		if err != nil {
-			return nil, err
-		}
 
-		hasTarget := strings.TrimSpace(selector) != "" || strings.TrimSpace(ariaRole) != "" || strings.TrimSpace(ariaName) != ""
+		hasTarget := spec.isSet()
 		if crop != nil && hasTarget {
-			return nil, errors.New("--crop cannot be combined with selector/aria targeting")
+			return nil, invalidArgf("--crop cannot be combined with element targeting")
 		}
 
 		path, err := SafeArtifactPath(artifactDir, pathArg, fmt.Sprintf("screenshot-%d.png", NowMS()))
@@ -228,10 +252,8 @@ func RunCall(page playwright.Page, name string, args map[string]interface{}, art
 
 		opts := playwright.PageScreenshotOptions{Path: playwright.String(path), FullPage: playwright.Bool(fullPage)}
 		var clip *playwright.Rect
-		var spec TargetSpec
 
 		if hasTarget {
-			spec = TargetSpec{Selector: selector, AriaRole: ariaRole, AriaName: ariaName, Nth: nth, Timeout: targetTimeout}
 			box, err := resolveBounds(page, spec)
 			if err != nil {
 				return nil, err
@@ -264,10 +286,7 @@ func RunCall(page playwright.Page, name string, args map[string]interface{}, art
 
 		res := RunResult{"path": path}
 		if clip != nil {
-			res["selector"] = selector
-			res["aria_role"] = ariaRole
-			res["aria_name"] = ariaName
-			res["nth"] = spec.effectiveNth()
+			res["target"] = spec.describe()
 			res["clip"] = map[string]float64{"x": clip.X, "y": clip.Y, "width": clip.Width, "height": clip.Height}
 		}
 		return res, nil
@@ -277,6 +296,10 @@ func RunCall(page playwright.Page, name string, args map[string]interface{}, art
 		if err != nil {
 			return nil, err
 		}
+		maxTokens, err := optionalInt(args, "max_tokens", 0)
+		if err != nil {
+			return nil, err
+		}
 		path, err := SafeArtifactPath(artifactDir, pathArg, fmt.Sprintf("page-%d.html", NowMS()))
 		if err != nil {
 			return nil, err
@@ -288,85 +311,438 @@ func RunCall(page playwright.Page, name string, args map[string]interface{}, art
 		if err := osWriteFile(path, []byte(html)); err != nil {
 			return nil, err
 		}
-		return RunResult{"path": path}, nil
+		res := RunResult{"path": path, "estimated_tokens": EstimateTokens(html)}
+		if maxTokens > 0 {
+			// The file keeps everything; the inline copy fits the budget.
+			res["html"], res["truncated"] = clipToTokens(html, maxTokens)
+			res["max_tokens"] = maxTokens
+		}
+		return res, nil
 
 	case "bounds":
-		selector, err := optionalString(args, "selector", "")
+		spec, err := optionalTargetSpec(args, 5_000)
 		if err != nil {
 			return nil, err
 		}
-		ariaRole, err := optionalString(args, "aria_role", "")
+		if !spec.isSet() {
+			return nil, errTargetRequired()
+		}
+		box, err := resolveBounds(page, spec)
 		if err != nil {
 			return nil, err
 		}
-		ariaName, err := optionalString(args, "aria_name", "")
+		return RunResult{
+			"target": spec.describe(),
+			"x":      box.X,
+			"y":      box.Y,
+			"width":  box.Width,
+			"height": box.Height,
+		}, nil
+
+	case "inspect_ref":
+		spec, err := optionalTargetSpec(args, 5_000)
+		if err != nil {
+			return nil, err
+		}
+		if !spec.isSet() {
+			return nil, errTargetRequired()
+		}
+		maxHTML, err := optionalInt(args, "max_html_chars", 2_000)
 		if err != nil {
 			return nil, err
 		}
-		nth, err := optionalInt(args, "nth", 1)
+		if maxHTML < 0 {
+			return nil, invalidArgf("max_html_chars must be >= 0")
+		}
+		listeners, err := optionalBool(args, "listeners", true)
 		if err != nil {
 			return nil, err
 		}
-		timeoutMs, err := optionalInt(args, "timeout_ms", 5_000)
+		maxTokens, err := optionalInt(args, "max_tokens", 0)
 		if err != nil {
 			return nil, err
 		}
+		el, err := resolveElement(page, spec)
+		if err != nil {
+			return nil, err
+		}
+		report, err := InspectElement(page, el, InspectOptions{MaxHTMLChars: maxHTML, Listeners: listeners})
+		_ = el.Dispose()
+		if err != nil {
+			return nil, err
+		}
+		res := RunResult{}
+		for key, value := range report {
+			res[key] = value
+		}
+		for key, value := range spec.result() {
+			res[key] = value
+		}
+		if html, ok := res["html"].(string); ok && maxTokens > 0 {
+			if clipped, cut := clipToTokens(html, maxTokens); cut {
+				res["html"], res["html_truncated"] = clipped, true
+			}
+			res["max_tokens"] = maxTokens
+		}
+		res["estimated_tokens"] = jsonTokens(res)
+		return res, nil
 
-		spec := TargetSpec{Selector: selector, AriaRole: ariaRole, AriaName: ariaName, Nth: nth, Timeout: timeoutMs}
-		box, err := resolveBounds(page, spec)
+	case "links":
+		include, err := optionalStringList(args, "include")
+		if err != nil {
+			return nil, err
+		}
+		exclude, err := optionalStringList(args, "exclude")
+		if err != nil {
+			return nil, err
+		}
+		scope, err := optionalString(args, "scope", "all")
+		if err != nil {
+			return nil, err
+		}
+		limit, err := optionalInt(args, "limit", 0)
+		if err != nil {
+			return nil, err
+		}
+		maxTokens, err := optionalInt(args, "max_tokens", 0)
+		if err != nil {
+			return nil, err
+		}
+		links, total, err := CollectLinks(page, LinkOptions{Include: include, Exclude: exclude, Scope: scope, Limit: limit})
+		if err != nil {
+			return nil, err
+		}
+		links = links[:countWithinTokens(len(links), maxTokens, func(i int) int { return jsonTokens(links[i]) })]
+		res := RunResult{
+			"url":              page.URL(),
+			"count":            len(links),
+			"total":            total,
+			"links":            links,
+			"estimated_tokens": jsonTokens(links),
+		}
+		if maxTokens > 0 {
+			res["max_tokens"] = maxTokens
+		}
+		return res, nil
+
+	case "table":
+		spec, err := optionalTargetSpec(args, 5_000)
+		if err != nil {
+			return nil, err
+		}
+		expandSpans, err := optionalBool(args, "expand_spans", true)
+		if err != nil {
+			return nil, err
+		}
+		maxRows, err := optionalInt(args, "max_rows", 500)
+		if err != nil {
+			return nil, err
+		}
+		format, err := optionalString(args, "format", "json")
+		if err != nil {
+			return nil, err
+		}
+		if format != "json" && format != "csv" {
+			return nil, invalidArgf("invalid format '%s' (expected json or csv)", format)
+		}
+		pathArg, err := optionalString(args, "path", "")
+		if err != nil {
+			return nil, err
+		}
+		maxTokens, err := optionalInt(args, "max_tokens", 0)
+		if err != nil {
+			return nil, err
+		}
+
+		table, err := ExtractTable(page, TableOptions{
+			Target:      spec,
+			ExpandSpans: expandSpans,
+			MaxRows:     maxRows,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if format == "json" && maxTokens > 0 {
+			// Rows written to CSV are not returned, so only JSON output is
+			// held to the budget. The headers are always kept.
+			budget := max(maxTokens-jsonTokens(table.Headers), 1)
+			table.Rows = table.Rows[:countWithinTokens(len(table.Rows), budget, func(i int) int { return jsonTokens(table.Rows[i]) })]
+		}
+		res := RunResult{
+			"kind":       table.Kind,
+			"headers":    table.Headers,
+			"row_count":  len(table.Rows),
+			"total_rows": table.TotalRows,
+			"truncated":  len(table.Rows) < table.TotalRows,
+		}
+		if spec.isSet() {
+			res["target"] = spec.describe()
+		}
+		if maxTokens > 0 {
+			res["max_tokens"] = maxTokens
+		}
+		if format == "csv" {
+			path, err := SafeArtifactPath(artifactDir, pathArg, fmt.Sprintf("table-%d.csv", NowMS()))
+			if err != nil {
+				return nil, err
+			}
+			data, err := tableCSV(table.Headers, table.Rows)
+			if err != nil {
+				return nil, err
+			}
+			if err := osWriteFile(path, data); err != nil {
+				return nil, err
+			}
+			res["path"] = path
+			return res, nil
+		}
+		res["rows"] = table.Rows
+		res["estimated_tokens"] = jsonTokens(table.Headers) + jsonTokens(table.Rows)
+		return res, nil
+
+	case "forms":
+		formSel, err := optionalString(args, "form", "")
+		if err != nil {
+			return nil, err
+		}
+		forms, err := CollectForms(page)
+		if err != nil {
+			return nil, err
+		}
+		forms, err = selectForms(forms, formSel)
+		if err != nil {
+			return nil, err
+		}
+		return RunResult{"url": page.URL(), "count": len(forms), "forms": forms}, nil
+
+	case "fill_form":
+		values, pageOrder, err := parseFormValues(args["values"])
+		if err != nil {
+			return nil, err
+		}
+		formSel, err := optionalString(args, "form", "")
+		if err != nil {
+			return nil, err
+		}
+		timeoutMs, err := optionalInt(args, "timeout_ms", 15_000)
+		if err != nil {
+			return nil, err
+		}
+		filled, err := FillForm(page, values, FillFormOptions{Form: formSel, TimeoutMs: timeoutMs, PageOrder: pageOrder})
 		if err != nil {
 			return nil, err
 		}
 		return RunResult{
-			"selector":  selector,
-			"aria_role": ariaRole,
-			"aria_name": ariaName,
-			"nth":       spec.effectiveNth(),
-			"x":         box.X,
-			"y":         box.Y,
-			"width":     box.Width,
-			"height":    box.Height,
+			"ok":        len(filled.Unmatched) == 0 && len(filled.Failed) == 0,
+			"filled":    filled.Filled,
+			"unmatched": filled.Unmatched,
+			"failed":    filled.Failed,
 		}, nil
-	}
 
-	return nil, fmt.Errorf("unknown call '%s'", name)
-}
+	case "click_xy", "mouse_move", "mouse_down", "mouse_up", "mouse_wheel":
+		return runMouse(page, name, args)
+
+	case "scroll":
+		spec, err := optionalTargetSpec(args, 5_000)
+		if err != nil {
+			return nil, err
+		}
+		dx, hasDX, err := optionalFloat(args, "dx")
+		if err != nil {
+			return nil, err
+		}
+		dy, hasDY, err := optionalFloat(args, "dy")
+		if err != nil {
+			return nil, err
+		}
+		to, err := optionalString(args, "to", "")
+		if err != nil {
+			return nil, err
+		}
+		if to != "" && (hasDX || hasDY) {
+			return nil, invalidArgf("use either to or dx/dy, not both")
+		}
+		state, err := Scroll(page, spec, ScrollOptions{DX: dx, DY: dy, HasDelta: hasDX || hasDY, To: to})
+		if err != nil {
+			return nil, err
+		}
+		res := RunResult(state)
+		if spec.isSet() {
+			res["target"] = spec.describe()
+		}
+		return res, nil
 
-func RunActions(page playwright.Page, calls []map[string]interface{}, artifactDir string) (ActionsResult, error) {
-	results := []map[string]interface{}{}
-	snapshotText := ""
+	case "scroll_into_view_ref":
+		spec, err := optionalTargetSpec(args, 5_000)
+		if err != nil {
+			return nil, err
+		}
+		if !spec.isSet() {
+			return nil, errTargetRequired()
+		}
+		block, err := optionalString(args, "block", "")
+		if err != nil {
+			return nil, err
+		}
+		el, err := resolveElement(page, spec)
+		if err != nil {
+			return nil, err
+		}
+		state, err := ScrollIntoView(page, el, block, spec.timeoutMs())
+		_ = el.Dispose()
+		if err != nil {
+			return nil, err
+		}
+		res := spec.result()
+		for key, value := range state {
+			res[key] = value
+		}
+		return res, nil
 
-	for _, call := range calls {
-		nameVal, ok := call["name"]
-		if !ok {
-			return ActionsResult{}, errors.New("each call must include name")
+	case "harvest":
+		container, err := optionalTargetSpec(args, 5_000)
+		if err != nil {
+			return nil, err
+		}
+		maxDurationMs, err := optionalInt(args, "max_duration_ms", 30_000)
+		if err != nil {
+			return nil, err
+		}
+		if maxDurationMs < 0 {
+			return nil, invalidArgf("max_duration_ms must be >= 0")
+		}
+		rowSelector, err := optionalString(args, "row_selector", "")
+		if err != nil {
+			return nil, err
+		}
+		interactiveOnly, err := optionalBool(args, "interactive_only", true)
+		if err != nil {
+			return nil, err
 		}
-		name, ok := nameVal.(string)
-		if !ok || strings.TrimSpace(name) == "" {
-			return ActionsResult{}, errors.New("each call must include non-empty string 'name'")
+		maxItems, err := optionalInt(args, "max_items", 500)
+		if err != nil {
+			return nil, err
 		}
-		argsVal, ok := call["arguments"]
-		if !ok || argsVal == nil {
-			argsVal = map[string]interface{}{}
+		idleRounds, err := optionalInt(args, "idle_rounds", 3)
+		if err != nil {
+			return nil, err
 		}
-		args, ok := argsVal.(map[string]interface{})
-		if !ok {
-			return ActionsResult{}, errors.New("call 'arguments' must be an object")
+		settleMs, err := optionalInt(args, "settle_ms", 300)
+		if err != nil {
+			return nil, err
+		}
+		scrollPx, _, err := optionalFloat(args, "scroll_px")
+		if err != nil {
+			return nil, err
+		}
+		inlineLimit, err := optionalInt(args, "inline_limit", 100)
+		if err != nil {
+			return nil, err
+		}
+		pathArg, err := optionalString(args, "path", "")
+		if err != nil {
+			return nil, err
 		}
 
-		res, err := RunCall(page, name, args, artifactDir)
+		harvest, err := Harvest(page, HarvestOptions{
+			Container:       container,
+			RowSelector:     rowSelector,
+			InteractiveOnly: interactiveOnly,
+			MaxItems:        maxItems,
+			MaxDurationMs:   maxDurationMs,
+			IdleRounds:      idleRounds,
+			SettleMs:        settleMs,
+			ScrollPx:        scrollPx,
+		})
 		if err != nil {
-			return ActionsResult{}, err
+			return nil, err
+		}
+		res := RunResult{
+			"count":       len(harvest.Items),
+			"rounds":      harvest.Rounds,
+			"stop_reason": harvest.StopReason,
 		}
-		entry := map[string]interface{}{"name": name, "result": res}
-		results = append(results, entry)
-		if name == "snapshot" {
-			if snap, ok := res["snapshot"].(string); ok {
-				snapshotText = snap
+		if pathArg != "" || len(harvest.Items) > inlineLimit {
+			path, err := writeHarvestArtifact(artifactDir, pathArg, harvest.Items)
+			if err != nil {
+				return nil, err
 			}
+			res["path"] = path
+			return res, nil
 		}
+		res["items"] = harvest.Items
+		return res, nil
+	}
+
+	return nil, invalidArgf("unknown call '%s'", name)
+}
+
+func runSnapshot(page playwright.Page, args map[string]interface{}) (RunResult, error) {
+	engine, err := optionalString(args, "engine", "simple")
+	if err != nil {
+		return nil, err
+	}
+	format, err := optionalString(args, "format", "list")
+	if err != nil {
+		return nil, err
+	}
+	interactiveOnly, err := optionalBool(args, "interactive_only", true)
+	if err != nil {
+		return nil, err
+	}
+	includeHeadings, err := optionalBool(args, "include_headings", true)
+	if err != nil {
+		return nil, err
+	}
+	maxItems, err := optionalInt(args, "max_items", 80)
+	if err != nil {
+		return nil, err
+	}
+	maxTokens, err := optionalInt(args, "max_tokens", 0)
+	if err != nil {
+		return nil, err
+	}
+	defaultMaxChars := 8000
+	if maxTokens > 0 {
+		// The token budget governs; keep the char cap out of its way.
+		defaultMaxChars = maxTokens * 16
+	}
+	maxChars, err := optionalInt(args, "max_chars", defaultMaxChars)
+	if err != nil {
+		return nil, err
+	}
+	withLocators, err := optionalBool(args, "with_locators", false)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := GetSnapshot(page, SnapshotOptions{
+		Engine:          engine,
+		Format:          format,
+		InteractiveOnly: interactiveOnly,
+		IncludeHeadings: includeHeadings,
+		MaxItems:        maxItems,
+		MaxChars:        maxChars,
+		MaxTokens:       maxTokens,
+		WithLocators:    withLocators,
+	})
+	if err != nil {
+		return nil, err
 	}
-	return ActionsResult{Results: results, Snapshot: snapshotText}, nil
+	res := RunResult{
+		"url":              page.URL(),
+		"title":            safeTitle(page),
+		"engine":           engine,
+		"format":           format,
+		"snapshot":         snap.Yaml,
+		"items":            snap.Items,
+		"estimated_tokens": snap.EstimatedTokens,
+	}
+	if maxTokens > 0 {
+		res["max_tokens"] = maxTokens
+		res["dropped_items"] = snap.DroppedItems
+	}
+	return res, nil
 }
 
 func getWaitUntil(value string) *playwright.WaitUntilState {
@@ -422,7 +798,7 @@ func waitPlaywright(page playwright.Page, state string, timeoutMs int, minWaitMs
 	}, nil
 }
 
-func waitPerf(page playwright.Page, state string, timeoutMs int, minWaitMs int) (RunResult, error) {
+func waitPerf(page playwright.Page, state string, ignore networkIgnore, timeoutMs int, minWaitMs int) (RunResult, error) {
 	pollInterval := 50 * time.Millisecond
 	start := time.Now()
 	if minWaitMs > 0 {
@@ -440,8 +816,11 @@ func waitPerf(page playwright.Page, state string, timeoutMs int, minWaitMs int)
 				if rs, ok := m["readyState"].(string); ok {
 					lastReady = rs
 				}
-				if pending, ok := asInt(m["pendingRequests"]); ok {
-					lastPending = pending
+				lastPending = 0
+				for _, u := range toStringSlice(m["pendingUrls"]) {
+					if !ignore.matches(u) {
+						lastPending++
+					}
 				}
 			}
 		}
@@ -465,53 +844,28 @@ func waitPerf(page playwright.Page, state string, timeoutMs int, minWaitMs int)
 	}, nil
 }
 
+// perfLoadStateJS lists resources still loading; waitPerf drops the ignored
+// ones.
 const perfLoadStateJS = `() => {
   const doc = globalThis.document;
   const perf = globalThis.performance;
   const readyState = doc && typeof doc.readyState === "string" ? doc.readyState : "unknown";
   if (!perf || typeof perf.getEntriesByType !== "function" || typeof perf.now !== "function") {
-    return { readyState, pendingRequests: 0 };
+    return { readyState, pendingUrls: [] };
   }
 
   const now = perf.now();
   const resources = perf.getEntriesByType("resource") || [];
 
-  const adPatterns = [
-    "doubleclick.net",
-    "googlesyndication.com",
-    "googletagmanager.com",
-    "google-analytics.com",
-    "facebook.net",
-    "connect.facebook.net",
-    "analytics",
-    "ads",
-    "tracking",
-    "pixel",
-    "hotjar.com",
-    "clarity.ms",
-    "mixpanel.com",
-    "segment.com",
-    "newrelic.com",
-    "nr-data.net",
-    "/tracker/",
-    "/collector/",
-    "/beacon/",
-    "/telemetry/",
-    "/log/",
-    "/events/",
-    "/track.",
-    "/metrics/",
-  ];
 
   const nonCriticalTypes = ["img", "image", "icon", "font"];
 
-  let pending = 0;
+  const pending = [];
   for (const entry of resources) {
     if (!entry || entry.responseEnd !== 0) continue;
     const url = String(entry.name || "");
 
     if (!url || url.startsWith("data:") || url.length > 500) continue;
-    if (adPatterns.some((p) => url.includes(p))) continue;
 
     const loadingDuration = now - (entry.startTime || 0);
     if (loadingDuration > 10000) continue;
@@ -522,9 +876,9 @@ const perfLoadStateJS = `() => {
     const isImageUrl = /\.(jpg|jpeg|png|gif|webp|svg|ico)(\?|$)/i.test(url);
     if (isImageUrl && loadingDuration > 3000) continue;
 
-    pending++;
+    pending.push(url);
   }
-  return { readyState, pendingRequests: pending };
+  return { readyState, pendingUrls: pending };
 }`
 
 func readyStateSatisfies(ready string, state string) bool {
@@ -538,11 +892,11 @@ func readyStateSatisfies(ready string, state string) bool {
 func requireString(args map[string]interface{}, key string) (string, error) {
 	raw, ok := args[key]
 	if !ok {
-		return "", fmt.Errorf("expected non-empty string '%s'", key)
+		return "", invalidArgf("expected non-empty string '%s'", key)
 	}
 	str, ok := raw.(string)
 	if !ok || strings.TrimSpace(str) == "" {
-		return "", fmt.Errorf("expected non-empty string '%s'", key)
+		return "", invalidArgf("expected non-empty string '%s'", key)
 	}
 	return str, nil
 }
@@ -554,11 +908,42 @@ func optionalString(args map[string]interface{}, key string, def string) (string
 	}
 	str, ok := raw.(string)
 	if !ok || strings.TrimSpace(str) == "" {
-		return "", fmt.Errorf("expected string '%s'", key)
+		return "", invalidArgf("expected string '%s'", key)
 	}
 	return str, nil
 }
 
+func optionalStringList(args map[string]interface{}, key string) ([]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	switch t := raw.(type) {
+	case string:
+		out := []string{}
+		for _, part := range strings.Split(t, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+		return out, nil
+	case []string:
+		return t, nil
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, v := range t {
+			str, ok := v.(string)
+			if !ok {
+				return nil, invalidArgf("expected string or string array '%s'", key)
+			}
+			out = append(out, str)
+		}
+		return out, nil
+	default:
+		return nil, invalidArgf("expected string or string array '%s'", key)
+	}
+}
+
 func optionalBool(args map[string]interface{}, key string, def bool) (bool, error) {
 	raw, ok := args[key]
 	if !ok {
@@ -566,7 +951,7 @@ func optionalBool(args map[string]interface{}, key string, def bool) (bool, erro
 	}
 	b, ok := raw.(bool)
 	if !ok {
-		return false, fmt.Errorf("expected boolean '%s'", key)
+		return false, invalidArgf("expected boolean '%s'", key)
 	}
 	return b, nil
 }
@@ -578,11 +963,37 @@ func optionalInt(args map[string]interface{}, key string, def int) (int, error)
 	}
 	if i, ok := asInt(raw); ok {
 		if i < 0 {
-			return 0, fmt.Errorf("expected non-negative integer '%s'", key)
+			return 0, invalidArgf("expected non-negative integer '%s'", key)
 		}
 		return i, nil
 	}
-	return 0, fmt.Errorf("expected non-negative integer '%s'", key)
+	return 0, invalidArgf("expected non-negative integer '%s'", key)
+}
+
+// optionalFloat reads a number that may be negative (offsets, scroll deltas).
+func optionalFloat(args map[string]interface{}, key string) (float64, bool, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+	switch t := raw.(type) {
+	case float64:
+		return t, true, nil
+	case float32:
+		return float64(t), true, nil
+	case int:
+		return float64(t), true, nil
+	case int64:
+		return float64(t), true, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		if err != nil {
+			return 0, false, invalidArgf("expected number '%s'", key)
+		}
+		return f, true, nil
+	default:
+		return 0, false, invalidArgf("expected number '%s'", key)
+	}
 }
 
 func asInt(v interface{}) (int, bool) {
@@ -608,18 +1019,18 @@ func optionalCrop(args map[string]interface{}) (*playwright.Rect, error) {
 
 	toVals := func(seq []interface{}) ([]int, error) {
 		if len(seq) != 4 {
-			return nil, errors.New("crop must have 4 items: x,y,width,height")
+			return nil, invalidArgf("crop must have 4 items: x,y,width,height")
 		}
 		vals := make([]int, 4)
 		for i, v := range seq {
 			n, ok := asInt(v)
 			if !ok || n < 0 {
-				return nil, errors.New("crop values must be non-negative integers")
+				return nil, invalidArgf("crop values must be non-negative integers")
 			}
 			vals[i] = n
 		}
 		if vals[2] < 1 || vals[3] < 1 {
-			return nil, errors.New("crop width/height must be positive")
+			return nil, invalidArgf("crop width/height must be positive")
 		}
 		if vals[2] > 2000 {
 			vals[2] = 2000
@@ -635,13 +1046,13 @@ func optionalCrop(args map[string]interface{}) (*playwright.Rect, error) {
 	case string:
 		parts := strings.Split(t, ",")
 		if len(parts) != 4 {
-			return nil, errors.New("--crop must be x,y,width,height")
+			return nil, invalidArgf("--crop must be x,y,width,height")
 		}
 		seq := make([]interface{}, 0, 4)
 		for _, p := range parts {
 			p = strings.TrimSpace(p)
 			if p == "" {
-				return nil, errors.New("--crop must be x,y,width,height")
+				return nil, invalidArgf("--crop must be x,y,width,height")
 			}
 			seq = append(seq, p)
 		}
@@ -649,7 +1060,7 @@ func optionalCrop(args map[string]interface{}) (*playwright.Rect, error) {
 		for _, s := range seq {
 			num, err := strconv.Atoi(s.(string))
 			if err != nil {
-				return nil, errors.New("crop values must be integers")
+				return nil, invalidArgf("crop values must be integers")
 			}
 			valsSeq = append(valsSeq, num)
 		}
@@ -672,12 +1083,21 @@ func optionalCrop(args map[string]interface{}) (*playwright.Rect, error) {
 		}
 		vals = v
 	default:
-		return nil, errors.New("crop must be string, array, or object")
+		return nil, invalidArgf("crop must be string, array, or object")
 	}
 
 	return &playwright.Rect{X: float64(vals[0]), Y: float64(vals[1]), Width: float64(vals[2]), Height: float64(vals[3])}, nil
 }
 
+// decodeJSResult converts a page.Evaluate result into a typed value via JSON.
+func decodeJSResult(raw interface{}, out interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
 func safeTitle(page playwright.Page) string {
 	if page == nil {
 		return ""
@@ -688,11 +1108,14 @@ func safeTitle(page playwright.Page) string {
 	return ""
 }
 
+// isTimeout reports whether err is a Playwright timeout or one of our own
+// polling loops running out (ErrTimeout).
 func isTimeout(err error) bool {
-	if err == nil {
-		return false
+	var te *ToolError
+	if errors.As(err, &te) {
+		return te.Code == ErrTimeout
 	}
-	return strings.Contains(strings.ToLower(err.Error()), "timeout")
+	return errors.Is(err, playwright.ErrTimeout)
 }
 
 func osWriteFile(path string, data []byte) error {
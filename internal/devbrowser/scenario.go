@@ -0,0 +1,433 @@
+package devbrowser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"gopkg.in/yaml.v3"
+)
+
+// ScenarioFile is a parsed scenario file: shared vars, suite-level
+// setup/teardown and named scenarios.
+type ScenarioFile struct {
+	Name      string
+	Page      string
+	Vars      map[string]interface{}
+	Setup     []ScenarioStep
+	Teardown  []ScenarioStep
+	Scenarios []Scenario
+}
+
+type Scenario struct {
+	Name     string
+	Setup    []ScenarioStep
+	Steps    []ScenarioStep
+	Teardown []ScenarioStep
+}
+
+// ScenarioStep is a labelled actions call.
+type ScenarioStep struct {
+	Label string
+	Call  map[string]interface{}
+}
+
+// scenarioStepKeys are the step keys handed to RunActions as they are; any
+// other single key names the tool.
+var scenarioStepKeys = map[string]bool{
+	"id": true, "retry": true, "continue_on_error": true, "if": true,
+	"repeat_until": true, "max_repeats": true, "repeat_interval_ms": true,
+}
+
+// scenarioShorthand maps tools to the argument a scalar value fills, so
+// `goto: https://example.com` or `press: Enter` work.
+var scenarioShorthand = map[string]string{
+	"goto":      "url",
+	"press":     "key",
+	"click_ref": "ref",
+	"find":      "selector",
+}
+
+// stepTimeoutTools are the tools whose waiting is bounded by timeout_ms. A
+// step timeout on any other tool would not bound anything, so it is rejected.
+var stepTimeoutTools = map[string]bool{
+	"goto": true, "back": true, "forward": true, "reload": true,
+	"click_ref": true, "fill_ref": true, "fill_form": true, "wait": true,
+	"expect": true, "find": true, "bounds": true, "table": true,
+	"scroll_into_view_ref": true,
+}
+
+type rawScenario struct {
+	Name     string                   `yaml:"name"`
+	Setup    []map[string]interface{} `yaml:"setup"`
+	Steps    []map[string]interface{} `yaml:"steps"`
+	Teardown []map[string]interface{} `yaml:"teardown"`
+}
+
+// ParseScenarioFile reads a YAML scenario file. A file with top-level steps
+// and no scenarios is one scenario named after the file.
+func ParseScenarioFile(data []byte, defaultName string) (*ScenarioFile, error) {
+	var raw struct {
+		Name      string                   `yaml:"name"`
+		Page      string                   `yaml:"page"`
+		Vars      map[string]interface{}   `yaml:"vars"`
+		Setup     []map[string]interface{} `yaml:"setup"`
+		Steps     []map[string]interface{} `yaml:"steps"`
+		Teardown  []map[string]interface{} `yaml:"teardown"`
+		Scenarios []rawScenario            `yaml:"scenarios"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, invalidArgf("invalid scenario file: %v", err)
+	}
+	file := &ScenarioFile{Name: raw.Name, Page: raw.Page, Vars: raw.Vars}
+	if file.Name == "" {
+		file.Name = defaultName
+	}
+	if file.Vars == nil {
+		file.Vars = map[string]interface{}{}
+	}
+
+	scenarios := raw.Scenarios
+	if len(raw.Steps) > 0 {
+		if len(scenarios) > 0 {
+			return nil, invalidArgf("use either top-level steps or scenarios, not both")
+		}
+		scenarios = []rawScenario{{Name: file.Name, Steps: raw.Steps}}
+	}
+	if len(scenarios) == 0 {
+		return nil, invalidArgf("scenario file has no scenarios")
+	}
+
+	var err error
+	if file.Setup, err = parseScenarioSteps(raw.Setup, "setup"); err != nil {
+		return nil, err
+	}
+	if file.Teardown, err = parseScenarioSteps(raw.Teardown, "teardown"); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for i, rs := range scenarios {
+		sc := Scenario{Name: strings.TrimSpace(rs.Name)}
+		if sc.Name == "" {
+			sc.Name = fmt.Sprintf("scenario %d", i+1)
+		}
+		if seen[sc.Name] {
+			return nil, invalidArgf("duplicate scenario name '%s'", sc.Name)
+		}
+		seen[sc.Name] = true
+		if len(rs.Steps) == 0 {
+			return nil, invalidArgf("scenario '%s' has no steps", sc.Name)
+		}
+		if sc.Setup, err = parseScenarioSteps(rs.Setup, sc.Name+" setup"); err != nil {
+			return nil, err
+		}
+		if sc.Steps, err = parseScenarioSteps(rs.Steps, sc.Name); err != nil {
+			return nil, err
+		}
+		if sc.Teardown, err = parseScenarioSteps(rs.Teardown, sc.Name+" teardown"); err != nil {
+			return nil, err
+		}
+		file.Scenarios = append(file.Scenarios, sc)
+	}
+	return file, nil
+}
+
+func parseScenarioSteps(raw []map[string]interface{}, where string) ([]ScenarioStep, error) {
+	steps := []ScenarioStep{}
+	for i, item := range raw {
+		step, err := parseScenarioStep(item)
+		if err != nil {
+			return nil, invalidArgf("%s step %d: %v", where, i+1, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// parseScenarioStep turns {name, <tool>: args, timeout_ms, ...} (or
+// {tool, args}) into an actions call. timeout_ms is passed to the tool unless
+// its arguments set one; only stepTimeoutTools accept it.
+func parseScenarioStep(raw map[string]interface{}) (ScenarioStep, error) {
+	step := ScenarioStep{Call: map[string]interface{}{}}
+	if label, ok := raw["name"]; ok {
+		s, ok := label.(string)
+		if !ok {
+			return step, fmt.Errorf("name must be a string")
+		}
+		step.Label = s
+	}
+
+	tool := ""
+	var args interface{}
+	if t, ok := raw["tool"]; ok {
+		s, ok := t.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return step, fmt.Errorf("tool must be a non-empty string")
+		}
+		tool, args = s, raw["args"]
+	}
+	for key, value := range raw {
+		switch {
+		case key == "name", key == "tool", key == "args", key == "timeout_ms":
+		case scenarioStepKeys[key]:
+			step.Call[key] = value
+		case tool != "":
+			return step, fmt.Errorf("unexpected key '%s' (tool is already %s)", key, tool)
+		default:
+			tool, args = key, value
+		}
+	}
+	if tool == "" {
+		return step, fmt.Errorf("no tool given")
+	}
+
+	argMap := map[string]interface{}{}
+	switch val := args.(type) {
+	case nil:
+	case map[string]interface{}:
+		for k, v := range val {
+			argMap[k] = v
+		}
+	default:
+		key, ok := scenarioShorthand[tool]
+		if !ok {
+			return step, fmt.Errorf("%s needs an arguments map", tool)
+		}
+		argMap[key] = val
+	}
+	if timeout, ok := raw["timeout_ms"]; ok {
+		if !stepTimeoutTools[tool] {
+			names := make([]string, 0, len(stepTimeoutTools))
+			for name := range stepTimeoutTools {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return step, fmt.Errorf("timeout_ms has no effect on %s (supported by: %s)", tool, strings.Join(names, ", "))
+		}
+		if _, set := argMap["timeout_ms"]; !set {
+			argMap["timeout_ms"] = timeout
+		}
+	}
+	step.Call["name"] = tool
+	step.Call["arguments"] = argMap
+	if step.Label == "" {
+		step.Label = tool
+	}
+	return step, nil
+}
+
+type ScenarioOptions struct {
+	CallOptions
+	// Vars override the file's vars.
+	Vars map[string]interface{}
+	// Only limits the run to these scenario names when non-empty.
+	Only []string
+}
+
+type StepReport struct {
+	Name       string     `json:"name"`
+	Tool       string     `json:"tool"`
+	Phase      string     `json:"phase"`
+	Status     string     `json:"status"`
+	DurationMS int64      `json:"duration_ms"`
+	Error      *ToolError `json:"error,omitempty"`
+	Artifacts  []string   `json:"artifacts,omitempty"`
+}
+
+type ScenarioReport struct {
+	Name       string       `json:"name"`
+	Status     string       `json:"status"`
+	DurationMS int64        `json:"duration_ms"`
+	Steps      []StepReport `json:"steps"`
+	Error      *ToolError   `json:"error,omitempty"`
+	Artifacts  []string     `json:"artifacts,omitempty"`
+}
+
+type RunReport struct {
+	Name        string           `json:"name"`
+	Passed      int              `json:"passed"`
+	Failed      int              `json:"failed"`
+	DurationMS  int64            `json:"duration_ms"`
+	Setup       []StepReport     `json:"setup,omitempty"`
+	Teardown    []StepReport     `json:"teardown,omitempty"`
+	Scenarios   []ScenarioReport `json:"scenarios"`
+	StartedAt   time.Time        `json:"started_at"`
+	setupErr    *ToolError
+	teardownErr *ToolError
+}
+
+const (
+	stepPassed  = "passed"
+	stepFailed  = "failed"
+	stepSkipped = "skipped"
+)
+
+// RunScenarios runs file's scenarios on page in order. Suite setup runs
+// once before them and suite teardown once after; each scenario's teardown
+// runs even when its steps fail. Failures are recorded in the report, not
+// returned.
+func RunScenarios(page playwright.Page, file *ScenarioFile, opts ScenarioOptions) *RunReport {
+	start := time.Now()
+	report := &RunReport{Name: file.Name, Scenarios: []ScenarioReport{}, StartedAt: start}
+	vars := map[string]interface{}{}
+	for k, v := range file.Vars {
+		vars[k] = v
+	}
+	for k, v := range opts.Vars {
+		vars[k] = v
+	}
+	only := map[string]bool{}
+	for _, name := range opts.Only {
+		only[name] = true
+	}
+
+	report.Setup, report.setupErr = runScenarioPhase(page, file.Setup, "setup", vars, opts.CallOptions)
+	for _, sc := range file.Scenarios {
+		if len(only) > 0 && !only[sc.Name] {
+			continue
+		}
+		var sr ScenarioReport
+		if report.setupErr != nil {
+			sr = ScenarioReport{Name: sc.Name, Status: stepFailed, Steps: []StepReport{}, Error: report.setupErr}
+		} else {
+			sr = runScenario(page, sc, vars, opts.CallOptions)
+		}
+		if sr.Status == stepPassed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Scenarios = append(report.Scenarios, sr)
+	}
+	report.Teardown, report.teardownErr = runScenarioPhase(page, file.Teardown, "teardown", vars, opts.CallOptions)
+	report.DurationMS = time.Since(start).Milliseconds()
+	return report
+}
+
+func runScenario(page playwright.Page, sc Scenario, vars map[string]interface{}, call CallOptions) ScenarioReport {
+	start := time.Now()
+	sr := ScenarioReport{Name: sc.Name, Status: stepPassed}
+
+	// Setup and steps share one batch so steps can reference setup results.
+	phases := []string{}
+	steps := append(append([]ScenarioStep{}, sc.Setup...), sc.Steps...)
+	for range sc.Setup {
+		phases = append(phases, "setup")
+	}
+	for range sc.Steps {
+		phases = append(phases, "step")
+	}
+	reports, err := runScenarioSteps(page, steps, phases, vars, call)
+	sr.Steps = reports
+	if err != nil {
+		sr.Status = stepFailed
+		sr.Error = err
+		if shot, shotErr := RunCall(page, "screenshot", map[string]interface{}{"path": fmt.Sprintf("run-%s-%d.png", artifactSlug(sc.Name), NowMS())}, call); shotErr == nil {
+			if path, ok := shot["path"].(string); ok {
+				sr.Artifacts = append(sr.Artifacts, path)
+			}
+		}
+	}
+
+	teardown, tdErr := runScenarioPhase(page, sc.Teardown, "teardown", vars, call)
+	sr.Steps = append(sr.Steps, teardown...)
+	if tdErr != nil && sr.Error == nil {
+		sr.Status = stepFailed
+		sr.Error = tdErr
+	}
+	for _, step := range sr.Steps {
+		sr.Artifacts = append(sr.Artifacts, step.Artifacts...)
+	}
+	sr.DurationMS = time.Since(start).Milliseconds()
+	return sr
+}
+
+func runScenarioPhase(page playwright.Page, steps []ScenarioStep, phase string, vars map[string]interface{}, call CallOptions) ([]StepReport, *ToolError) {
+	phases := make([]string, len(steps))
+	for i := range phases {
+		phases[i] = phase
+	}
+	return runScenarioSteps(page, steps, phases, vars, call)
+}
+
+// runScenarioSteps runs steps as one actions batch and reports each one;
+// steps after a failure are reported as skipped.
+func runScenarioSteps(page playwright.Page, steps []ScenarioStep, phases []string, vars map[string]interface{}, call CallOptions) ([]StepReport, *ToolError) {
+	reports := []StepReport{}
+	if len(steps) == 0 {
+		return reports, nil
+	}
+	calls := make([]map[string]interface{}, len(steps))
+	for i, step := range steps {
+		calls[i] = step.Call
+	}
+	res, err := RunActions(page, calls, ActionsOptions{CallOptions: call, Vars: vars})
+	for i, step := range steps {
+		tool, _ := step.Call["name"].(string)
+		sr := StepReport{Name: step.Label, Tool: tool, Phase: phases[i], Status: stepSkipped}
+		if i < len(res.Results) {
+			sr.Status, sr.DurationMS, sr.Error, sr.Artifacts = stepOutcome(res.Results[i])
+		}
+		reports = append(reports, sr)
+	}
+	if err != nil {
+		return reports, ClassifyError(err)
+	}
+	return reports, nil
+}
+
+func stepOutcome(entry map[string]interface{}) (string, int64, *ToolError, []string) {
+	duration, _ := entry["duration_ms"].(int64)
+	if skipped, _ := entry["skipped"].(bool); skipped {
+		return stepSkipped, duration, nil, nil
+	}
+	if ok, _ := entry["ok"].(bool); !ok {
+		te, _ := entry["error"].(*ToolError)
+		return stepFailed, duration, te, nil
+	}
+	var artifacts []string
+	if res, ok := entry["result"].(RunResult); ok {
+		if path, ok := res["path"].(string); ok && path != "" {
+			artifacts = append(artifacts, path)
+		}
+	}
+	return stepPassed, duration, nil, artifacts
+}
+
+// Err summarises failures for the exit status, using the first failure's
+// code.
+func (r *RunReport) Err() error {
+	var first *ToolError
+	for _, sc := range r.Scenarios {
+		if sc.Status != stepPassed && first == nil {
+			first = sc.Error
+		}
+	}
+	if first == nil {
+		first = r.setupErr
+	}
+	if first == nil {
+		first = r.teardownErr
+	}
+	if first == nil {
+		return nil
+	}
+	msg := fmt.Sprintf("%d of %d scenarios failed: %s", r.Failed, len(r.Scenarios), first.Message)
+	if r.Failed == 0 {
+		msg = "suite setup/teardown failed: " + first.Message
+	}
+	return &ToolError{Code: first.Code, Message: msg, Details: first.Details, Err: first}
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+func artifactSlug(name string) string {
+	slug := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		return "scenario"
+	}
+	return slug
+}
@@ -0,0 +1,147 @@
+package devbrowser
+
+import (
+	"strings"
+	"testing"
+)
+
+const smokeYAML = `
+name: smoke
+vars:
+  base: https://shop.test
+setup:
+  - goto: ${vars.base}
+scenarios:
+  - name: home loads
+    steps:
+      - name: open home
+        goto: ${vars.base}/
+        timeout_ms: 20000
+      - expect: {title_contains: Shop}
+  - name: search
+    setup:
+      - press: Escape
+    steps:
+      - id: box
+        find: {aria_role: searchbox}
+      - tool: fill_ref
+        args: {ref: "${steps.box.result.ref}", text: shoes}
+        retry: 2
+    teardown:
+      - goto: about:blank
+`
+
+func TestParseScenarioFile(t *testing.T) {
+	file, err := ParseScenarioFile([]byte(smokeYAML), "fallback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "smoke" || file.Vars["base"] != "https://shop.test" || len(file.Setup) != 1 || len(file.Scenarios) != 2 {
+		t.Fatalf("unexpected file %+v", file)
+	}
+
+	open := file.Scenarios[0].Steps[0]
+	args := open.Call["arguments"].(map[string]interface{})
+	if open.Label != "open home" || open.Call["name"] != "goto" || args["url"] != "${vars.base}/" || args["timeout_ms"] != 20000 {
+		t.Fatalf("unexpected step %+v", open)
+	}
+	if label := file.Scenarios[0].Steps[1].Label; label != "expect" {
+		t.Fatalf("label should default to the tool, got %q", label)
+	}
+
+	search := file.Scenarios[1]
+	if len(search.Setup) != 1 || len(search.Teardown) != 1 {
+		t.Fatalf("unexpected search scenario %+v", search)
+	}
+	fill := search.Steps[1].Call
+	if fill["name"] != "fill_ref" || fill["retry"] != 2 || search.Steps[0].Call["id"] != "box" {
+		t.Fatalf("unexpected calls %v / %v", search.Steps[0].Call, fill)
+	}
+}
+
+func TestParseScenarioFileSingleScenario(t *testing.T) {
+	file, err := ParseScenarioFile([]byte("steps:\n  - press: Enter\n"), "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "checkout" || len(file.Scenarios) != 1 || file.Scenarios[0].Name != "checkout" {
+		t.Fatalf("unexpected file %+v", file)
+	}
+}
+
+func TestParseScenarioFileErrors(t *testing.T) {
+	bad := []string{
+		"name: empty\n",
+		"steps: [{press: Enter}]\nscenarios: [{name: a, steps: [{press: Enter}]}]\n",
+		"scenarios: [{name: a, steps: [{press: Enter}]}, {name: a, steps: [{press: Tab}]}]\n",
+		"scenarios: [{name: a}]\n",
+		"steps: [{snapshot: 3}]\n",
+		"steps: [{press: Enter, goto: x}]\n",
+		"steps: [{name: nothing}]\n",
+		"steps: [\n",
+		"steps: [{press: Enter, timeout_ms: 500}]\n",
+		"steps: [{snapshot: {}, timeout_ms: 500}]\n",
+	}
+	for _, doc := range bad {
+		if _, err := ParseScenarioFile([]byte(doc), "x"); err == nil {
+			t.Fatalf("expected error for %q", doc)
+		}
+	}
+}
+
+func TestParseScenarioStepTimeout(t *testing.T) {
+	step, err := parseScenarioStep(map[string]interface{}{"wait": map[string]interface{}{"text": "Saved"}, "timeout_ms": 2000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args := step.Call["arguments"].(map[string]interface{}); args["timeout_ms"] != 2000 {
+		t.Fatalf("timeout_ms should reach the tool, got %+v", args)
+	}
+	if _, err := parseScenarioStep(map[string]interface{}{"screenshot": map[string]interface{}{}, "timeout_ms": 2000}); err == nil {
+		t.Fatal("expected timeout_ms to be rejected for screenshot")
+	}
+}
+
+func TestRunReportJUnit(t *testing.T) {
+	report := &RunReport{
+		Name:       "smoke",
+		Passed:     1,
+		Failed:     1,
+		DurationMS: 2500,
+		Scenarios: []ScenarioReport{
+			{Name: "home", Status: stepPassed, DurationMS: 1200, Steps: []StepReport{{Name: "open", Tool: "goto", Phase: "step", Status: stepPassed, DurationMS: 800}}},
+			{
+				Name: "search", Status: stepFailed, DurationMS: 1300,
+				Error:     &ToolError{Code: ErrAssertionFailed, Message: "expect failed <x>"},
+				Artifacts: []string{"/tmp/run-search.png"},
+			},
+		},
+	}
+	data, err := report.JUnitXML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xml := string(data)
+	for _, want := range []string{
+		`<testsuites name="smoke" tests="2" failures="1" time="2.500">`,
+		`<testcase classname="smoke" name="home" time="1.200">`,
+		`<failure message="expect failed &lt;x&gt;" type="assertion_failed">`,
+		`artifact: /tmp/run-search.png`,
+		`[step] open (goto) passed 800ms`,
+	} {
+		if !strings.Contains(xml, want) {
+			t.Fatalf("junit output missing %q:\n%s", want, xml)
+		}
+	}
+
+	err = report.Err()
+	te, ok := err.(*ToolError)
+	if !ok || te.Code != ErrAssertionFailed || !strings.HasPrefix(te.Message, "1 of 2 scenarios failed") {
+		t.Fatalf("unexpected report error %v", err)
+	}
+	report.Failed = 0
+	report.Scenarios = report.Scenarios[:1]
+	if err := report.Err(); err != nil {
+		t.Fatalf("passing report should have no error, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type ScrollOptions struct {
+	DX       float64
+	DY       float64
+	HasDelta bool
+	To       string
+}
+
+// scrollJS scrolls el (or the page when el is null) and reports where it
+// ended up. Without a delta or edge it scrolls down by 90% of the visible
+// height, like Page Down.
+const scrollJS = `(el, opts) => {
+  const target = el || document.scrollingElement || document.documentElement;
+  const before = { x: target.scrollLeft, y: target.scrollTop };
+  const to = opts.to || "";
+  if (to === "top") target.scrollTop = 0;
+  else if (to === "bottom") target.scrollTop = target.scrollHeight;
+  else if (to === "left") target.scrollLeft = 0;
+  else if (to === "right") target.scrollLeft = target.scrollWidth;
+  else if (opts.hasDelta) target.scrollBy({ left: opts.dx, top: opts.dy, behavior: "instant" });
+  else target.scrollBy({ left: 0, top: Math.round(target.clientHeight * 0.9), behavior: "instant" });
+
+  const maxX = Math.max(0, target.scrollWidth - target.clientWidth);
+  const maxY = Math.max(0, target.scrollHeight - target.clientHeight);
+  return {
+    scroll_x: target.scrollLeft,
+    scroll_y: target.scrollTop,
+    max_x: maxX,
+    max_y: maxY,
+    moved: target.scrollLeft !== before.x || target.scrollTop !== before.y,
+    at_top: target.scrollTop <= 0,
+    at_bottom: target.scrollTop >= maxY - 1,
+    at_left: target.scrollLeft <= 0,
+    at_right: target.scrollLeft >= maxX - 1,
+  };
+}`
+
+const pageScrollPositionJS = `() => {
+  const target = document.scrollingElement || document.documentElement;
+  return { scroll_x: target.scrollLeft, scroll_y: target.scrollTop };
+}`
+
+// Scroll scrolls the container named by spec, or the page when spec is empty.
+func Scroll(page playwright.Page, spec TargetSpec, opts ScrollOptions) (map[string]interface{}, error) {
+	to := strings.ToLower(strings.TrimSpace(opts.To))
+	switch to {
+	case "", "top", "bottom", "left", "right":
+	default:
+		return nil, invalidArgf("invalid to '%s' (expected top, bottom, left, right)", opts.To)
+	}
+	opts.To = to
+	payload := scrollPayload(opts)
+
+	var raw interface{}
+	var err error
+	if spec.isSet() {
+		el, elErr := resolveElement(page, spec)
+		if elErr != nil {
+			return nil, elErr
+		}
+		raw, err = el.Evaluate(scrollJS, payload)
+		_ = el.Dispose()
+	} else {
+		raw, err = page.Evaluate("(opts) => ("+scrollJS+")(null, opts)", payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+	state, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected scroll result")
+	}
+	return state, nil
+}
+
+func scrollPayload(opts ScrollOptions) map[string]interface{} {
+	return map[string]interface{}{"dx": opts.DX, "dy": opts.DY, "hasDelta": opts.HasDelta, "to": opts.To}
+}
+
+// ScrollIntoView scrolls el into the viewport. block is "" (only if needed)
+// or one of start, center, end, nearest.
+func ScrollIntoView(page playwright.Page, el playwright.ElementHandle, block string, timeoutMs int) (map[string]interface{}, error) {
+	block = strings.ToLower(strings.TrimSpace(block))
+	switch block {
+	case "":
+		if err := el.ScrollIntoViewIfNeeded(playwright.ElementHandleScrollIntoViewIfNeededOptions{
+			Timeout: playwright.Float(float64(timeoutMs)),
+		}); err != nil {
+			return nil, err
+		}
+	case "start", "center", "end", "nearest":
+		if _, err := el.Evaluate(`(el, block) => el.scrollIntoView({ block, inline: "nearest", behavior: "instant" })`, block); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, invalidArgf("invalid block '%s' (expected start, center, end, nearest)", block)
+	}
+
+	res := map[string]interface{}{}
+	if box, err := el.BoundingBox(); err == nil && box != nil {
+		res["box"] = map[string]float64{"x": box.X, "y": box.Y, "width": box.Width, "height": box.Height}
+		vp := viewportSize(page)
+		res["in_viewport"] = box.X+box.Width > 0 && box.Y+box.Height > 0 && box.X < float64(vp.Width) && box.Y < float64(vp.Height)
+	}
+	if raw, err := page.Evaluate(pageScrollPositionJS); err == nil {
+		if pos, ok := raw.(map[string]interface{}); ok {
+			res["scroll_x"] = pos["scroll_x"]
+			res["scroll_y"] = pos["scroll_y"]
+		}
+	}
+	return res, nil
+}
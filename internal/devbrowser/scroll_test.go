@@ -0,0 +1,69 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestScrollRejectsUnknownEdge(t *testing.T) {
+	_, err := Scroll(nil, TargetSpec{}, ScrollOptions{To: "middle"})
+	if te, ok := err.(*ToolError); !ok || te.Code != ErrInvalidArgument {
+		t.Fatalf("expected invalid_argument, got %v", err)
+	}
+}
+
+func TestScrollIntoViewRejectsUnknownBlock(t *testing.T) {
+	_, err := ScrollIntoView(nil, nil, "top", 1000)
+	if te, ok := err.(*ToolError); !ok || te.Code != ErrInvalidArgument {
+		t.Fatalf("expected invalid_argument, got %v", err)
+	}
+}
+
+// runScrollJS runs scrollJS against a fake 1000px-tall container showing
+// 200px at a time, starting at scrollTop top.
+func runScrollJS(t *testing.T, top int, opts ScrollOptions) map[string]interface{} {
+	t.Helper()
+	payload, err := json.Marshal(scrollPayload(opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runSnapshotJS(t, fmt.Sprintf(`
+const scroll = %s;
+const el = {
+  _top: %d, _left: 0, scrollHeight: 1000, clientHeight: 200, scrollWidth: 300, clientWidth: 300,
+  get scrollTop() { return this._top; },
+  set scrollTop(v) { this._top = Math.max(0, Math.min(v, this.scrollHeight - this.clientHeight)); },
+  get scrollLeft() { return this._left; },
+  set scrollLeft(v) { this._left = Math.max(0, Math.min(v, this.scrollWidth - this.clientWidth)); },
+  scrollBy({ left, top }) { this.scrollLeft = this._left + left; this.scrollTop = this._top + top; },
+};
+console.log(JSON.stringify(scroll(el, %s)));
+`, scrollJS, top, payload))
+}
+
+func TestScrollJS(t *testing.T) {
+	cases := []struct {
+		name   string
+		top    int
+		opts   ScrollOptions
+		y      float64
+		moved  bool
+		bottom bool
+	}{
+		{"page down", 0, ScrollOptions{}, 180, true, false},
+		{"delta", 100, ScrollOptions{DY: 50, HasDelta: true}, 150, true, false},
+		{"to bottom", 0, ScrollOptions{To: "bottom"}, 800, true, true},
+		{"already at bottom", 800, ScrollOptions{}, 800, false, true},
+		{"to top", 300, ScrollOptions{To: "top"}, 0, true, false},
+	}
+	for _, tc := range cases {
+		res := runScrollJS(t, tc.top, tc.opts)
+		if res["scroll_y"] != tc.y || res["moved"] != tc.moved || res["at_bottom"] != tc.bottom {
+			t.Fatalf("%s: unexpected result %+v", tc.name, res)
+		}
+		if res["max_y"] != float64(800) {
+			t.Fatalf("%s: max_y = %v, want 800", tc.name, res["max_y"])
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package devbrowser
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// sessionTools are the calls kept in a page's session log. Read-only tools
+// (snapshot, bounds, console, ...) are left out; mutating tools without a
+// Playwright equivalent are kept so the export can flag them.
+var sessionTools = map[string]bool{
+	"goto": true, "back": true, "forward": true, "reload": true,
+	"click_ref": true, "fill_ref": true, "press": true,
+	"wait": true, "expect": true,
+	"click_xy": true, "mouse_wheel": true, "mouse_move": true, "mouse_down": true, "mouse_up": true,
+	"scroll": true, "scroll_into_view_ref": true, "fill_form": true, "harvest": true,
+}
+
+// SessionEntry is one successful call in a page's session log. Target holds
+// locator hints for the call's ref, captured before the call ran since refs
+// do not outlive the page state.
+type SessionEntry struct {
+	Tool      string                 `json:"tool"`
+	Args      map[string]interface{} `json:"args"`
+	Target    map[string]interface{} `json:"target,omitempty"`
+	URLBefore string                 `json:"url_before"`
+	URLAfter  string                 `json:"url_after"`
+	TimeMS    int64                  `json:"time_ms"`
+}
+
+// StartSessionLog begins (or restarts) logging calls to path.
+func StartSessionLog(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, nil, 0o644)
+}
+
+// resetSessionLog empties an active log when its page is (re)created, so an
+// export never mixes calls from an earlier page with the same name.
+func resetSessionLog(path string) {
+	if _, err := os.Stat(path); err == nil {
+		_ = os.WriteFile(path, nil, 0o644)
+	}
+}
+
+// SessionLogPath is where calls on page are logged for export.
+func SessionLogPath(profile, page string) string {
+	return filepath.Join(StateDir(profile), "sessions", url.PathEscape(page)+".jsonl")
+}
+
+// sessionCall is a call being logged; begin returns nil when the call is not
+// logged.
+type sessionCall struct {
+	path  string
+	entry SessionEntry
+}
+
+// beginSessionCall starts an entry for a call that changes state. Logging
+// only happens while the log file exists: StartSessionLog creates it, so
+// pages that are never exported do not pay for locator hints.
+func beginSessionCall(page playwright.Page, name string, args map[string]interface{}, path string) *sessionCall {
+	if path == "" || !sessionTools[name] {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	call := &sessionCall{path: path, entry: SessionEntry{Tool: name, Args: args, URLBefore: page.URL()}}
+	if ref, _ := args["ref"].(string); strings.TrimSpace(ref) != "" {
+		call.entry.Target = refLocatorHints(page, ref)
+	}
+	switch name {
+	case "fill_ref":
+		if isSecretField(page, args) {
+			call.entry.Args = redactArgs(args, "text", "${vars.password}")
+		}
+	case "fill_form":
+		call.entry.Args = redactArgs(args, "values", formFieldNames(args["values"]))
+	}
+	return call
+}
+
+// secretFieldJS flags inputs whose values must not be written to disk.
+const secretFieldJS = `(el) => {
+  const type = (el.type || "").toLowerCase();
+  const auto = (el.getAttribute("autocomplete") || "").toLowerCase();
+  const key = ((el.name || "") + " " + (el.id || "")).toLowerCase();
+  return type === "password" || /password|one-time-code/.test(auto) || /pass|secret|token|otp|api.?key/.test(key);
+}`
+
+// isSecretField reports whether a fill_ref call targets a password-like
+// field. A field that cannot be checked counts as secret.
+func isSecretField(page playwright.Page, args map[string]interface{}) bool {
+	spec, err := actionTarget(args, "text")
+	if err != nil {
+		return true
+	}
+	spec.Timeout = 1_000
+	el, err := resolveElement(page, spec)
+	if err != nil {
+		return true
+	}
+	defer el.Dispose()
+	secret, err := el.Evaluate(secretFieldJS)
+	return err != nil || secret == true
+}
+
+// redactArgs returns a copy of args with key replaced by value.
+func redactArgs(args map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// formFieldNames keeps the field names of fill_form values, in either
+// shape, and drops what was typed into them.
+func formFieldNames(values interface{}) interface{} {
+	switch val := values.(type) {
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for name := range val {
+			out[name] = "${vars." + name + "}"
+		}
+		return out
+	case []interface{}:
+		out := []interface{}{}
+		for _, item := range val {
+			entry, _ := item.(map[string]interface{})
+			field, _ := entry["field"].(string)
+			out = append(out, map[string]interface{}{"field": field, "value": "${vars." + field + "}"})
+		}
+		return out
+	}
+	return nil
+}
+
+// finish appends the call to the log. Logging is best effort and never
+// fails the call.
+func (c *sessionCall) finish(page playwright.Page) {
+	if c == nil {
+		return
+	}
+	c.entry.URLAfter = page.URL()
+	c.entry.TimeMS = NowMS()
+	data, err := json.Marshal(c.entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(data, '\n'))
+}
+
+func refLocatorHints(page playwright.Page, ref string) map[string]interface{} {
+	el, err := SelectRef(page, ref, "simple")
+	if err != nil {
+		return nil
+	}
+	defer el.Dispose()
+	raw, err := el.Evaluate("(el) => globalThis.__devBrowser_locatorHints(el)")
+	if err != nil {
+		return nil
+	}
+	hints, _ := raw.(map[string]interface{})
+	role, _ := hints["role"].(string)
+	name, _ := hints["name"].(string)
+	if role == "" || name == "" {
+		return hints
+	}
+	// Keep the role hint only if Playwright's accessible name finds the
+	// element too; its position there is the nth the export needs.
+	if index, err := roleRefPosition(page, role, name, ref); err == nil && index >= 0 {
+		hints["nth"] = index + 1
+	} else {
+		delete(hints, "role")
+		delete(hints, "name")
+	}
+	return hints
+}
+
+// ReadSessionLog returns the logged calls, oldest first. A missing log is an
+// empty session.
+func ReadSessionLog(path string) ([]SessionEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []SessionEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries := []SessionEntry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry SessionEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -7,17 +7,23 @@ import (
 )
 
 type SnapshotOptions struct {
+	// Root limits the simple engine to the element's descendants.
+	Root            playwright.ElementHandle
 	Engine          string
 	Format          string
 	InteractiveOnly bool
 	IncludeHeadings bool
 	MaxItems        int
 	MaxChars        int
+	MaxTokens       int
+	WithLocators    bool
 }
 
 type SnapshotResult struct {
-	Yaml  string
-	Items []map[string]interface{}
+	Yaml            string
+	Items           []map[string]interface{}
+	EstimatedTokens int
+	DroppedItems    int
 }
 
 func ensureInjected(page playwright.Page, engine string) error {
@@ -62,6 +68,9 @@ func GetSnapshot(page playwright.Page, opts SnapshotOptions) (*SnapshotResult, e
 		"maxItems":        opts.MaxItems,
 		"maxChars":        opts.MaxChars,
 	}
+	if opts.Root != nil {
+		payload["root"] = opts.Root
+	}
 
 	raw, err := page.Evaluate("(opts) => globalThis.__devBrowser_getAISnapshot(opts)", payload)
 	if err != nil {
@@ -83,24 +92,169 @@ func GetSnapshot(page playwright.Page, opts SnapshotOptions) (*SnapshotResult, e
 		}
 	}
 
-	return &SnapshotResult{Yaml: yaml, Items: items}, nil
+	result := &SnapshotResult{Yaml: yaml, Items: items}
+	if opts.WithLocators {
+		if err := addItemLocators(page, result, opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.MaxTokens > 0 {
+		if err := applyTokenBudget(page, result, opts); err != nil {
+			return nil, err
+		}
+	}
+	result.EstimatedTokens = EstimateTokens(result.Yaml)
+	return result, nil
+}
+
+func applyTokenBudget(page playwright.Page, snap *SnapshotResult, opts SnapshotOptions) error {
+	if EstimateTokens(snap.Yaml) <= opts.MaxTokens {
+		return nil
+	}
+	if opts.Format != "list" {
+		snap.Yaml = truncateToTokens(snap.Yaml, opts.MaxTokens)
+		return nil
+	}
+
+	render := func(items []map[string]interface{}) (string, error) {
+		return renderSnapshotItems(page, items, opts, len(snap.Items))
+	}
+	items, yaml, dropped, err := fitItemsToTokens(snap.Items, opts.MaxTokens, render)
+	if err != nil {
+		return err
+	}
+	snap.Items = items
+	snap.Yaml = yaml
+	snap.DroppedItems = dropped
+	return nil
+}
+
+// renderSnapshotItems renders list-format items again after Go changed
+// them; total is the item count before any were dropped, for the truncation
+// note.
+func renderSnapshotItems(page playwright.Page, items []map[string]interface{}, opts SnapshotOptions, total int) (string, error) {
+	raw, err := page.Evaluate("(a) => globalThis.__devBrowser_buildYaml(a.items, a.opts)", map[string]interface{}{
+		"items": items,
+		"opts": map[string]interface{}{
+			"maxItems":  opts.MaxItems,
+			"maxChars":  opts.MaxChars,
+			"truncated": opts.MaxItems > 0 && total >= opts.MaxItems,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	text, _ := raw.(string)
+	return text, nil
+}
+
+// itemLocatorCandidates are the selectors that could match only one
+// snapshot item. The CSS ones are already known to be unique; Role still
+// has to be checked with Playwright.
+type itemLocatorCandidates struct {
+	TestID string `json:"test_id"`
+	Role   string `json:"role"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+}
+
+// pick returns the most stable selector: test id, role and name when
+// Playwright matches only the item, id, name attribute, the role with nth
+// when Playwright matches the item among others, then the CSS path. position
+// reports the item's index among the role's matches, or -1.
+func (c itemLocatorCandidates) pick(position func(selector string) (index, count int)) string {
+	if c.TestID != "" {
+		return c.TestID
+	}
+	index, count := -1, 0
+	if c.Role != "" {
+		index, count = position(c.Role)
+		if index == 0 && count == 1 {
+			return c.Role
+		}
+	}
+	switch {
+	case c.ID != "":
+		return c.ID
+	case c.Name != "":
+		return c.Name
+	case index >= 0:
+		return fmt.Sprintf("%s >> nth=%d", c.Role, index)
+	}
+	return c.Path
+}
+
+// addItemLocators gives each item a selector that matches only its element,
+// so the locator works as a selector for bounds, screenshot and click_ref.
+// Candidates are gathered in one page-side pass; role candidates then cost
+// one Playwright query each.
+func addItemLocators(page playwright.Page, snap *SnapshotResult, opts SnapshotOptions) error {
+	refs := make([]interface{}, 0, len(snap.Items))
+	for _, item := range snap.Items {
+		refs = append(refs, item["ref"])
+	}
+	raw, err := page.Evaluate(`(refs) => {
+  const all = globalThis.__devBrowserRefs || {};
+  return globalThis.__devBrowser_itemLocators(refs.map((ref) => all[ref] || null));
+}`, refs)
+	if err != nil {
+		return err
+	}
+	var candidates []*itemLocatorCandidates
+	if err := decodeJSResult(raw, &candidates); err != nil {
+		return err
+	}
+	for i, item := range snap.Items {
+		if i >= len(candidates) {
+			break
+		}
+		if candidates[i] == nil {
+			continue
+		}
+		ref, _ := item["ref"].(string)
+		selector := candidates[i].pick(func(selector string) (int, int) {
+			index, count, err := refPosition(page.Locator(selector), ref)
+			if err != nil {
+				return -1, 0
+			}
+			return index, count
+		})
+		if selector != "" {
+			item["locator"] = selector
+		}
+	}
+	if opts.Format != "list" {
+		return nil
+	}
+	yaml, err := renderSnapshotItems(page, snap.Items, opts, len(snap.Items))
+	if err != nil {
+		return err
+	}
+	snap.Yaml = yaml
+	return nil
 }
 
 func SelectRef(page playwright.Page, ref string, engine string) (playwright.ElementHandle, error) {
 	if err := ensureInjected(page, engine); err != nil {
 		return nil, err
 	}
-	handle, err := page.EvaluateHandle("(ref) => globalThis.__devBrowser_selectSnapshotRef(ref)", ref)
+	details := map[string]interface{}{"ref": ref}
+	handle, err := page.EvaluateHandle("(ref) => globalThis.__devBrowser_lookupSnapshotRef(ref)", ref)
 	if err != nil {
 		return nil, err
 	}
-	if handle == nil {
-		return nil, fmt.Errorf("ref '%s' not found", ref)
-	}
 	element := handle.AsElement()
 	if element == nil {
+		status, _ := handle.JSONValue()
 		_ = handle.Dispose()
-		return nil, fmt.Errorf("ref '%s' did not resolve to element", ref)
+		switch status {
+		case "stale":
+			return nil, NewToolError(ErrRefStale, fmt.Errorf("ref '%s' is stale (element detached); call snapshot again", ref), details)
+		case "no_snapshot":
+			return nil, NewToolError(ErrRefNotFound, fmt.Errorf("ref '%s' not found: no snapshot refs; call snapshot first", ref), details)
+		}
+		return nil, NewToolError(ErrRefNotFound, fmt.Errorf("ref '%s' not found; call snapshot again", ref), details)
 	}
 	return element, nil
 }
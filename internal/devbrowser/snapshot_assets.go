@@ -55,11 +55,7 @@ func ariaScript() string {
 	sb.WriteString("      const suffix = `\\n- [...] truncated (max_chars=${maxChars})`;\n")
 	sb.WriteString("      return text.slice(0, Math.max(0, maxChars - suffix.length)) + suffix;\n")
 	sb.WriteString("    }\n\n")
-	sb.WriteString("    const refsObject = {};\n")
-	sb.WriteString("    try {\n")
-	sb.WriteString("      for (const [ref, element] of snap.elements) refsObject[ref] = element;\n")
-	sb.WriteString("    } catch {\n")
-	sb.WriteString("    }\n")
+	sb.WriteString("    const refsObject = globalThis.__devBrowser_adoptRefs(snap);\n")
 	sb.WriteString("    globalThis.__devBrowserRefs = refsObject;\n\n")
 	sb.WriteString("    const items = [];\n")
 	sb.WriteString("    let currentHeading = null;\n")
@@ -213,6 +213,7 @@
       else if (item.pressed === true) suffix += " [pressed]";
       if (item.active) suffix += " [active]";
       if (item.cursorPointer) suffix += " [cursor=pointer]";
+      if (item.locator) suffix += ` [locator=${item.locator}]`;
       lines.push(`${indent}- ${item.role}${name}${suffix}`);
     }
     if (opts.truncated) lines.push(`- [...] truncated (max_items=${opts.maxItems})`);
@@ -232,7 +233,7 @@
     globalThis.__devBrowserRefs = {};
     const items = [];
     const state = { heading: null };
-    walk(document.documentElement, state, items, { maxItems, maxChars, interactiveOnly });
+    walk(opts.root || document.documentElement, state, items, { maxItems, maxChars, interactiveOnly });
     const truncated = items.length >= maxItems;
 
     const yaml = buildYaml(items, { maxItems, maxChars, truncated });
@@ -240,15 +241,25 @@
     return globalThis.__devBrowserLastSnapshot;
   }
 
-  function selectSnapshotRef(ref) {
+  // lookupSnapshotRef returns the ref's element, or a status string
+  // ("no_snapshot", "not_found" or "stale") the caller maps to an error code.
+  function lookupSnapshotRef(ref) {
     const refs = globalThis.__devBrowserRefs;
-    if (!refs) throw new Error("No snapshot refs found. Call snapshot first.");
+    if (!refs) return "no_snapshot";
     const el = refs[ref];
-    if (!el) throw new Error(`Ref "${ref}" not found. Call snapshot again.`);
-    if (!el.isConnected) throw new Error(`Ref "${ref}" is stale (element detached). Call snapshot again.`);
+    if (!el) return "not_found";
+    if (!el.isConnected) return "stale";
     return el;
   }
 
+  function selectSnapshotRef(ref) {
+    const found = lookupSnapshotRef(ref);
+    if (found === "no_snapshot") throw new Error("No snapshot refs found. Call snapshot first.");
+    if (found === "not_found") throw new Error(`Ref "${ref}" not found. Call snapshot again.`);
+    if (found === "stale") throw new Error(`Ref "${ref}" is stale (element detached). Call snapshot again.`);
+    return found;
+  }
+
   function clearRefOverlay() {
     const root = globalThis.__devBrowserRefOverlayRoot;
     if (root && root.parentNode) root.parentNode.removeChild(root);
@@ -332,9 +343,177 @@
     throw new Error(`Unknown snapshot engine: ${engine}`);
   }
 
+  function registerRef(el) {
+    if (!globalThis.__devBrowserRefs) globalThis.__devBrowserRefs = {};
+    const ref = ensureRef(el);
+    globalThis.__devBrowserRefs[ref] = el;
+    return ref;
+  }
+
+  // adoptRefs renumbers refs issued by another engine (the vendored aria
+  // snapshot keeps its own counter) from the shared counter, so a ref names
+  // one element whichever engine or helper handed it out.
+  function adoptRefs(snap) {
+    const remap = new Map();
+    const refs = {};
+    for (const [ref, el] of snap.elements) {
+      const own = ensureRef(el);
+      remap.set(ref, own);
+      refs[own] = el;
+    }
+    const visit = (node) => {
+      if (!node || typeof node === "string") return;
+      if (node.ref) node.ref = remap.get(node.ref);
+      for (const child of node.children || []) visit(child);
+    };
+    visit(snap.root);
+    snap.elements = new Map(Object.entries(refs));
+    snap.refs = new Map(Object.entries(refs).map(([ref, el]) => [el, ref]));
+    if (snap.iframeRefs) snap.iframeRefs = snap.iframeRefs.map((ref) => remap.get(ref)).filter(Boolean);
+    return refs;
+  }
+
+  // describeElement renders one element as a snapshot line with a fresh ref,
+  // for pointing at elements outside the last snapshot (e.g. a click blocker).
+  function describeElement(el) {
+    const ref = registerRef(el);
+    const st = getStates(el);
+    let name = getLabel(el);
+    if (name.length > 80) name = name.slice(0, 77) + "...";
+    const item = {
+      ref,
+      role: getRole(el),
+      name: name || null,
+      heading: null,
+      disabled: !!st.disabled,
+      checked: st.checked,
+      expanded: !!st.expanded,
+      selected: !!st.selected,
+      pressed: st.pressed,
+      active: !!st.active
+    };
+    item.line = buildYaml([item], { maxItems: 1, maxChars: 1000, truncated: false });
+    return item;
+  }
+
+  function cssEscape(value) {
+    if (globalThis.CSS && CSS.escape) return CSS.escape(value);
+    return String(value).replace(/[^a-zA-Z0-9_-]/g, (c) => "\\" + c);
+  }
+
+  function cssPath(el) {
+    const parts = [];
+    let cur = el;
+    while (cur && cur.nodeType === 1 && cur !== document.documentElement) {
+      if (cur.id) {
+        parts.unshift("#" + cssEscape(cur.id));
+        break;
+      }
+      let part = cur.tagName.toLowerCase();
+      const parent = cur.parentElement;
+      if (parent) {
+        const same = Array.from(parent.children).filter((c) => c.tagName === cur.tagName);
+        if (same.length > 1) part += ":nth-of-type(" + (same.indexOf(cur) + 1) + ")";
+      }
+      parts.unshift(part);
+      cur = parent;
+    }
+    return parts.join(" > ");
+  }
+
+  // cssTarget is a CSS selector for el: its test id, id or name attribute
+  // when it has one, otherwise its path from the root.
+  function cssTarget(el) {
+    const testId = el.getAttribute("data-testid");
+    if (testId) return `[data-testid=${JSON.stringify(testId)}]`;
+    if (el.id) return "#" + cssEscape(el.id);
+    const nameAttr = el.getAttribute("name");
+    if (nameAttr) return `${el.tagName.toLowerCase()}[name=${JSON.stringify(nameAttr)}]`;
+    return cssPath(el);
+  }
+
+  // targetOf describes el the way tools target elements: role and exact name
+  // when the snapshot would show them, otherwise a CSS selector. getLabel only
+  // approximates Playwright's accessible name, so a role target also carries
+  // the element's ref and a CSS fallback for the caller to check it against
+  // Playwright and set nth.
+  function targetOf(el) {
+    const selector = cssTarget(el);
+    const role = getRole(el);
+    const name = getLabel(el);
+    if (role !== "generic" && name && name.length <= 80) {
+      return { aria_role: role, aria_name: name, exact: true, check_ref: registerRef(el), fallback: selector };
+    }
+    return { selector };
+  }
+
+  // locatorHints lists every way a generated test could locate el; the
+  // exporter picks the most robust one present. The role hint is checked
+  // against Playwright by the caller, which also sets nth.
+  function locatorHints(el) {
+    const hints = { css: el.id ? "#" + cssEscape(el.id) : cssPath(el) };
+    const role = getRole(el);
+    const name = getLabel(el);
+    if (role !== "generic" && name && name.length <= 80) {
+      hints.role = role;
+      hints.name = name;
+    }
+    const testId = el.getAttribute("data-testid");
+    if (testId) hints.test_id = testId;
+    const label = el.labels && el.labels[0] ? el.labels[0].innerText.trim() : "";
+    if (label) hints.label = label;
+    const placeholder = el.getAttribute("placeholder");
+    if (placeholder) hints.placeholder = placeholder;
+    return hints;
+  }
+
+  // itemLocators lists, for each element, the selectors that could match
+  // only it: CSS candidates are kept only when querySelectorAll finds just
+  // el, while the role candidate is left for the caller to check against
+  // Playwright, whose accessible names getLabel only approximates.
+  function itemLocators(els) {
+    const cssUnique = (el, css) => {
+      try {
+        const found = document.querySelectorAll(css);
+        return found.length === 1 && found[0] === el;
+      } catch {
+        return false;
+      }
+    };
+
+    return els.map((el) => {
+      if (!el) return null;
+      const out = {};
+      const testId = el.getAttribute("data-testid");
+      if (testId) {
+        const css = `[data-testid=${JSON.stringify(testId)}]`;
+        if (cssUnique(el, css)) out.test_id = css;
+      }
+      const role = getRole(el);
+      const name = getLabel(el);
+      if (role !== "generic" && name && name.length <= 80) out.role = `role=${role}[name=${JSON.stringify(name)}s]`;
+      if (el.id && cssUnique(el, "#" + cssEscape(el.id))) out.id = "#" + cssEscape(el.id);
+      const nameAttr = el.getAttribute("name");
+      if (nameAttr) {
+        const css = `${el.tagName.toLowerCase()}[name=${JSON.stringify(nameAttr)}]`;
+        if (cssUnique(el, css)) out.name = css;
+      }
+      const path = cssPath(el);
+      if (cssUnique(el, path)) out.path = path;
+      return out;
+    });
+  }
+
   globalThis.__devBrowser_buildYaml = buildYaml;
+  globalThis.__devBrowser_targetOf = targetOf;
+  globalThis.__devBrowser_locatorHints = locatorHints;
+  globalThis.__devBrowser_itemLocators = itemLocators;
+  globalThis.__devBrowser_registerRef = registerRef;
+  globalThis.__devBrowser_adoptRefs = adoptRefs;
+  globalThis.__devBrowser_describeElement = describeElement;
   globalThis.__devBrowser_getAISnapshot = getAISnapshot;
   globalThis.__devBrowser_selectSnapshotRef = selectSnapshotRef;
+  globalThis.__devBrowser_lookupSnapshotRef = lookupSnapshotRef;
   globalThis.__devBrowser_drawRefOverlay = drawRefOverlay;
   globalThis.__devBrowser_clearRefOverlay = clearRefOverlay;
 })();
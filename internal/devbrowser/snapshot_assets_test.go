@@ -0,0 +1,165 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+)
+
+// runSnapshotJS evaluates the base snapshot script plus body under node and
+// decodes what body prints. DOM-free helpers only.
+func runSnapshotJS(t *testing.T, body string) map[string]interface{} {
+	t.Helper()
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node not installed")
+	}
+	out, err := exec.Command(node, "-e", baseScript()+"\n"+body).CombinedOutput()
+	if err != nil {
+		t.Fatalf("node: %v\n%s", err, out)
+	}
+	var res map[string]interface{}
+	if err := json.Unmarshal(out, &res); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	return res
+}
+
+func TestAdoptRefsSharesCounterWithRegisterRef(t *testing.T) {
+	res := runSnapshotJS(t, `
+const a = { id: "a" }, b = { id: "b" }, c = { id: "c" };
+const first = globalThis.__devBrowser_registerRef(a);
+// The aria engine numbers from its own counter, so it reuses "e1" for b.
+const snap = { elements: new Map([["e1", b], ["e2", a]]), root: { ref: "e1", children: [{ ref: "e2" }, "text"] } };
+globalThis.__devBrowserRefs = globalThis.__devBrowser_adoptRefs(snap);
+const later = globalThis.__devBrowser_registerRef(c);
+const refs = globalThis.__devBrowserRefs;
+console.log(JSON.stringify({
+  first, later,
+  root: snap.root.ref, child: snap.root.children[0].ref,
+  a: refs[first].id, b: refs[snap.root.ref].id, c: refs[later].id,
+  count: Object.keys(refs).length,
+}));
+`)
+	want := map[string]interface{}{
+		"first": "e1", "later": "e3", "root": "e2", "child": "e1",
+		"a": "a", "b": "b", "c": "c", "count": float64(3),
+	}
+	for key, value := range want {
+		if res[key] != value {
+			t.Fatalf("%s = %v, want %v (all: %v)", key, res[key], value, res)
+		}
+	}
+}
+
+func TestItemLocatorCandidates(t *testing.T) {
+	res := runSnapshotJS(t, `
+const make = (tag, text, attrs = {}) => ({
+  tagName: tag.toUpperCase(), innerText: text, nodeType: 1, parentElement: null,
+  getAttribute: (k) => (k in attrs ? attrs[k] : null),
+  hasAttribute: (k) => k in attrs,
+  getBoundingClientRect: () => ({ width: 10, height: 10 }),
+});
+const save = make("button", "Save");
+const tagged = make("button", "Go", { "data-testid": "go" });
+const named = make("input", "", { name: "q", type: "checkbox" });
+const all = [save, tagged, named];
+globalThis.document = {
+  documentElement: {},
+  querySelectorAll: (sel) => {
+    const m = sel.match(/^\[data-testid="(.*)"\]$/);
+    if (m) return all.filter((el) => el.getAttribute("data-testid") === m[1]);
+    if (sel === 'input[name="q"]') return [named];
+    return all.filter((el) => el.tagName.toLowerCase() === sel);
+  },
+};
+const [a, b, c] = globalThis.__devBrowser_itemLocators(all);
+console.log(JSON.stringify({ a: a.role, aPath: a.path || "", b: b.test_id, c: c.name, cRole: c.role || "" }));
+`)
+	want := map[string]interface{}{
+		"a":     `role=button[name="Save"s]`,
+		"aPath": "",
+		"b":     `[data-testid="go"]`,
+		"c":     `input[name="q"]`,
+		"cRole": "",
+	}
+	for key, value := range want {
+		if res[key] != value {
+			t.Fatalf("%s = %v, want %v", key, res[key], value)
+		}
+	}
+}
+
+func TestItemLocatorPickChecksRole(t *testing.T) {
+	role := `role=button[name="Save"s]`
+	cases := []struct {
+		name         string
+		c            itemLocatorCandidates
+		index, count int
+		want         string
+	}{
+		{"test id first", itemLocatorCandidates{TestID: "[data-testid=\"s\"]", Role: role}, 0, 1, "[data-testid=\"s\"]"},
+		{"unique role", itemLocatorCandidates{Role: role, ID: "#save"}, 0, 1, role},
+		{"shared role prefers id", itemLocatorCandidates{Role: role, ID: "#save"}, 1, 2, "#save"},
+		{"shared role nth", itemLocatorCandidates{Role: role, Path: "div > button"}, 1, 2, role + " >> nth=1"},
+		{"role misses element", itemLocatorCandidates{Role: role, Path: "div > button"}, -1, 1, "div > button"},
+		{"nothing unique", itemLocatorCandidates{Role: role}, -1, 0, ""},
+	}
+	for _, tc := range cases {
+		got := tc.c.pick(func(selector string) (int, int) {
+			if selector != role {
+				t.Fatalf("%s: checked %q", tc.name, selector)
+			}
+			return tc.index, tc.count
+		})
+		if got != tc.want {
+			t.Fatalf("%s: pick = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTargetOfEscapesSelectors(t *testing.T) {
+	res := runSnapshotJS(t, `
+const make = (tag, attrs = {}) => ({
+  tagName: tag.toUpperCase(), innerText: "", nodeType: 1, parentElement: null,
+  getAttribute: (k) => (k in attrs ? attrs[k] : null),
+  hasAttribute: (k) => k in attrs,
+});
+const tested = globalThis.__devBrowser_targetOf(make("div", { "data-testid": 'say "hi"' }));
+const named = globalThis.__devBrowser_targetOf(make("div", { name: 'a"]b' }));
+const role = globalThis.__devBrowser_targetOf(make("button", { "aria-label": "Go", name: "go" }));
+console.log(JSON.stringify({ tested: tested.selector, named: named.selector, fallback: role.fallback, ref: role.check_ref, nth: role.nth === undefined }));
+`)
+	want := map[string]interface{}{
+		"tested":   `[data-testid="say \"hi\""]`,
+		"named":    `div[name="a\"]b"]`,
+		"fallback": `button[name="go"]`,
+		"ref":      "e1",
+		"nth":      true,
+	}
+	for key, value := range want {
+		if res[key] != value {
+			t.Fatalf("%s = %v, want %v (all: %v)", key, res[key], value, res)
+		}
+	}
+}
+
+func TestLookupSnapshotRefStatus(t *testing.T) {
+	res := runSnapshotJS(t, `
+const before = globalThis.__devBrowser_lookupSnapshotRef("e1");
+const live = { id: "live", isConnected: true }, gone = { id: "gone", isConnected: false };
+globalThis.__devBrowserRefs = { e1: live, e2: gone };
+console.log(JSON.stringify({
+  before,
+  live: globalThis.__devBrowser_lookupSnapshotRef("e1").id,
+  stale: globalThis.__devBrowser_lookupSnapshotRef("e2"),
+  missing: globalThis.__devBrowser_lookupSnapshotRef("e9"),
+}));
+`)
+	want := map[string]interface{}{"before": "no_snapshot", "live": "live", "stale": "stale", "missing": "not_found"}
+	for key, value := range want {
+		if res[key] != value {
+			t.Fatalf("%s = %v, want %v (all: %v)", key, res[key], value, res)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package devbrowser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type TableOptions struct {
+	// Target names the table, a cell inside it or a wrapper around it. When
+	// unset, the nth table on the page is used.
+	Target      TargetSpec
+	ExpandSpans bool
+	MaxRows     int
+}
+
+type TableData struct {
+	Kind      string
+	Headers   []string
+	Rows      [][]string
+	TotalRows int
+}
+
+const tableSelector = `table, [role="table"], [role="grid"], [role="treegrid"]`
+
+const extractTableJS = `(el, opts) => {
+  const norm = (t) => (t || "").replace(/\s+/g, " ").trim();
+  const expand = !!opts.expandSpans;
+  const ariaTable = el.tagName !== "TABLE";
+
+  function ownedBy(node) {
+    return node.closest("table, [role=table], [role=grid], [role=treegrid]") === el;
+  }
+
+  const rows = [];
+  if (!ariaTable) {
+    for (const tr of el.rows) {
+      if (tr.closest("table") !== el) continue;
+      rows.push({
+        header: tr.parentElement && tr.parentElement.tagName === "THEAD",
+        cells: Array.from(tr.cells).map((c) => ({
+          text: norm(c.innerText || c.textContent),
+          th: c.tagName === "TH",
+          colspan: Math.max(1, c.colSpan || 1),
+          rowspan: Math.max(1, c.rowSpan || 1),
+        })),
+      });
+    }
+  } else {
+    for (const tr of el.querySelectorAll("[role=row]")) {
+      if (!ownedBy(tr)) continue;
+      const cells = Array.from(tr.querySelectorAll("[role=cell], [role=gridcell], [role=columnheader], [role=rowheader]"))
+        .filter((c) => c.closest("[role=row]") === tr);
+      rows.push({
+        header: !!tr.closest("[role=rowgroup]") && cells.length > 0 && cells.every((c) => c.getAttribute("role") === "columnheader"),
+        cells: cells.map((c) => ({
+          text: norm(c.innerText || c.textContent),
+          th: c.getAttribute("role") === "columnheader",
+          colspan: Math.max(1, parseInt(c.getAttribute("aria-colspan") || "1", 10) || 1),
+          rowspan: Math.max(1, parseInt(c.getAttribute("aria-rowspan") || "1", 10) || 1),
+        })),
+      });
+    }
+  }
+
+  const grid = [];
+  const pending = [];
+  for (const row of rows) {
+    const out = [];
+    let col = 0;
+    // Spanned slots always take a column so later cells stay aligned;
+    // without expand they are left empty instead of repeating the text.
+    const place = () => {
+      while (pending[col] && pending[col].left > 0) {
+        out.push(expand ? pending[col].text : "");
+        pending[col].left--;
+        col++;
+      }
+    };
+    for (const cell of row.cells) {
+      place();
+      for (let i = 0; i < cell.colspan; i++) {
+        out.push(i === 0 || expand ? cell.text : "");
+        if (cell.rowspan > 1) pending[col] = { text: cell.text, left: cell.rowspan - 1 };
+        col++;
+      }
+    }
+    place();
+    grid.push({ header: row.header || (row.cells.length > 0 && row.cells.every((c) => c.th)), cells: out });
+  }
+
+  let headers = [];
+  let body = grid;
+  if (grid.length && grid[0].header) {
+    headers = grid[0].cells;
+    body = grid.slice(1);
+    while (body.length && body[0].header) body = body.slice(1);
+  }
+  return {
+    kind: ariaTable ? (el.getAttribute("role") || "table") : "html",
+    headers,
+    rows: body.map((r) => r.cells),
+  };
+}`
+
+func ExtractTable(page playwright.Page, opts TableOptions) (*TableData, error) {
+	el, err := resolveTableElement(page, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer el.Dispose()
+
+	raw, err := el.Evaluate(extractTableJS, map[string]interface{}{"expandSpans": opts.ExpandSpans})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("unexpected table result")
+	}
+	data := &TableData{}
+	data.Kind, _ = m["kind"].(string)
+	data.Headers = toStringSlice(m["headers"])
+	if arr, ok := m["rows"].([]interface{}); ok {
+		for _, row := range arr {
+			data.Rows = append(data.Rows, toStringSlice(row))
+		}
+	}
+	data.TotalRows = len(data.Rows)
+	if opts.MaxRows > 0 && len(data.Rows) > opts.MaxRows {
+		data.Rows = data.Rows[:opts.MaxRows]
+	}
+	data.Headers, data.Rows = padTable(data.Headers, data.Rows)
+	return data, nil
+}
+
+func resolveTableElement(page playwright.Page, opts TableOptions) (playwright.ElementHandle, error) {
+	spec := opts.Target
+	if !spec.isSet() {
+		spec.Selector = tableSelector
+	}
+	el, err := resolveElement(page, spec)
+	if err != nil {
+		return nil, err
+	}
+	return closestTable(el)
+}
+
+// closestTable lets callers point at a cell or a wrapper: it walks up to the
+// enclosing table, or down to the first table inside el.
+func closestTable(el playwright.ElementHandle) (playwright.ElementHandle, error) {
+	handle, err := el.EvaluateHandle(`(el, sel) => el.closest(sel) || el.querySelector(sel)`, tableSelector)
+	_ = el.Dispose()
+	if err != nil {
+		return nil, err
+	}
+	table := handle.AsElement()
+	if table == nil {
+		_ = handle.Dispose()
+		return nil, errors.New("target is not a table and contains no table")
+	}
+	return table, nil
+}
+
+// padTable makes the headers and every row as wide as the widest of them so
+// the output is rectangular. Headers are only padded when there are any.
+func padTable(headers []string, rows [][]string) ([]string, [][]string) {
+	width := len(headers)
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	if len(headers) > 0 {
+		headers = padTableRow(headers, width)
+	}
+	out := make([][]string, len(rows))
+	for i, row := range rows {
+		out[i] = padTableRow(row, width)
+	}
+	return headers, out
+}
+
+func padTableRow(row []string, width int) []string {
+	if len(row) >= width {
+		return row
+	}
+	padded := make([]string, width)
+	copy(padded, row)
+	return padded
+}
+
+func tableCSV(headers []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if len(headers) > 0 {
+		if err := w.Write(headers); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func toStringSlice(v interface{}) []string {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return []string{}
+	}
+	out := make([]string, 0, len(arr))
+	for _, item := range arr {
+		str, _ := item.(string)
+		out = append(out, str)
+	}
+	return out
+}
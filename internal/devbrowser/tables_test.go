@@ -0,0 +1,95 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPadTable(t *testing.T) {
+	headers, rows := padTable([]string{"a", "b"}, [][]string{{"1"}, {"1", "2", "3"}})
+	if len(rows[0]) != 3 || len(rows[1]) != 3 {
+		t.Fatalf("expected rows padded to width 3, got %+v", rows)
+	}
+	if rows[0][0] != "1" || rows[0][2] != "" {
+		t.Fatalf("unexpected padding: %+v", rows[0])
+	}
+	if len(headers) != 3 || headers[1] != "b" || headers[2] != "" {
+		t.Fatalf("expected headers padded to width 3, got %+v", headers)
+	}
+
+	headers, rows = padTable(nil, [][]string{{"1", "2"}})
+	if len(headers) != 0 || len(rows[0]) != 2 {
+		t.Fatalf("headerless table should stay headerless, got %+v %+v", headers, rows)
+	}
+}
+
+// extractFakeTable runs extractTableJS against a fake HTML table whose rows
+// are given as cells of {text, th, colspan, rowspan}.
+func extractFakeTable(t *testing.T, rows string, expand bool) map[string]interface{} {
+	t.Helper()
+	return runSnapshotJS(t, fmt.Sprintf(`
+const extract = %s;
+const table = { tagName: "TABLE", rows: [] };
+for (const [i, cells] of (%s).entries()) {
+  table.rows.push({
+    closest: () => table,
+    parentElement: { tagName: i === 0 && cells.every((c) => c.th) ? "THEAD" : "TBODY" },
+    cells: cells.map((c) => ({ innerText: c.text, tagName: c.th ? "TH" : "TD", colSpan: c.colspan || 1, rowSpan: c.rowspan || 1 })),
+  });
+}
+console.log(JSON.stringify(extract(table, { expandSpans: %t })));
+`, extractTableJS, rows, expand))
+}
+
+func TestExtractTableSpans(t *testing.T) {
+	rows := `[
+  [{ text: "Team", th: true }, { text: "Q1", th: true }, { text: "Q2", th: true }],
+  [{ text: "North", rowspan: 2 }, { text: "Total", colspan: 2 }],
+  [{ text: "3" }, { text: "4" }],
+]`
+	cases := []struct {
+		expand bool
+		want   string
+	}{
+		{true, `[["North","Total","Total"],["North","3","4"]]`},
+		{false, `[["North","Total",""],["","3","4"]]`},
+	}
+	for _, tc := range cases {
+		res := extractFakeTable(t, rows, tc.expand)
+		got, _ := json.Marshal(res["rows"])
+		if string(got) != tc.want {
+			t.Fatalf("expand=%v: expected rows %s, got %s", tc.expand, tc.want, got)
+		}
+		headers, _ := json.Marshal(res["headers"])
+		if string(headers) != `["Team","Q1","Q2"]` {
+			t.Fatalf("expand=%v: unexpected headers %s", tc.expand, headers)
+		}
+	}
+}
+
+func TestExtractTableHeaderNarrowerThanRows(t *testing.T) {
+	res := extractFakeTable(t, `[
+  [{ text: "Name", th: true }, { text: "Scores", th: true, colspan: 2 }, { text: "", th: true }],
+  [{ text: "Ada" }, { text: "1" }, { text: "2" }, { text: "3" }, { text: "extra" }],
+]`, false)
+	headers, rows := padTable(toStringSlice(res["headers"]), [][]string{toStringSlice(res["rows"].([]interface{})[0])})
+	if strings.Join(headers, "|") != "Name|Scores|||" {
+		t.Fatalf("unexpected headers %q", headers)
+	}
+	if len(rows[0]) != len(headers) {
+		t.Fatalf("headers and rows differ in width: %q vs %q", headers, rows[0])
+	}
+}
+
+func TestTableCSV(t *testing.T) {
+	data, err := tableCSV([]string{"Name", "Note"}, [][]string{{"Ada", `says "hi", twice`}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Name,Note\nAda,\"says \"\"hi\"\", twice\"\n"
+	if string(data) != expected {
+		t.Fatalf("expected %q got %q", expected, string(data))
+	}
+}
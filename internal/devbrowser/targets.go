@@ -5,30 +5,58 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync/atomic"
 
 	"github.com/playwright-community/playwright-go"
 )
 
+// TargetSpec names one element. Exactly one of Ref, Selector, XPath, Text,
+// Label, Placeholder, TestID or AriaRole is set; Within scopes the search to
+// a parent target.
 type TargetSpec struct {
-	Selector string
-	AriaRole string
-	AriaName string
-	Nth      int
-	Timeout  int
+	Ref         string
+	Selector    string
+	XPath       string
+	Text        string
+	Label       string
+	Placeholder string
+	TestID      string
+	AriaRole    string
+	AriaName    string
+	Exact       bool
+	Nth         int
+	Within      *TargetSpec
+	Timeout     int
+}
+
+// targetKeys are the argument names of TargetSpec's element kinds, in the
+// order describe prints them.
+var targetKeys = []string{"ref", "selector", "xpath", "text", "label", "placeholder", "test_id", "aria_role"}
+
+func (s TargetSpec) fields() []string {
+	return []string{s.Ref, s.Selector, s.XPath, s.Text, s.Label, s.Placeholder, s.TestID, s.AriaRole}
 }
 
 func (s TargetSpec) describe() string {
-	parts := []string{}
-	if strings.TrimSpace(s.Selector) != "" {
-		parts = append(parts, fmt.Sprintf("selector=%q", s.Selector))
+	if strings.TrimSpace(s.Ref) != "" {
+		return fmt.Sprintf("ref=%q", s.Ref)
 	}
-	if strings.TrimSpace(s.AriaRole) != "" {
-		parts = append(parts, fmt.Sprintf("aria_role=%q", s.AriaRole))
+	parts := []string{}
+	for i, value := range s.fields() {
+		if strings.TrimSpace(value) != "" {
+			parts = append(parts, fmt.Sprintf("%s=%q", targetKeys[i], value))
+		}
 	}
 	if strings.TrimSpace(s.AriaName) != "" {
 		parts = append(parts, fmt.Sprintf("aria_name=%q", s.AriaName))
 	}
+	if s.Exact {
+		parts = append(parts, "exact")
+	}
 	parts = append(parts, fmt.Sprintf("nth=%d", s.effectiveNth()))
+	if s.Within != nil {
+		parts = append(parts, "within ("+s.Within.describe()+")")
+	}
 	return strings.Join(parts, " ")
 }
 
@@ -46,37 +74,145 @@ func (s TargetSpec) timeoutMs() int {
 	return s.Timeout
 }
 
-func resolveBounds(page playwright.Page, spec TargetSpec) (*playwright.Rect, error) {
-	selector := strings.TrimSpace(spec.Selector)
-	ariaRole := strings.TrimSpace(spec.AriaRole)
-	ariaName := strings.TrimSpace(spec.AriaName)
+func (s TargetSpec) isSet() bool {
+	for _, value := range s.fields() {
+		if strings.TrimSpace(value) != "" {
+			return true
+		}
+	}
+	return strings.TrimSpace(s.AriaName) != ""
+}
 
-	if selector == "" && ariaRole == "" {
-		return nil, errors.New("selector or aria_role is required")
+// optionalTargetSpec reads the target arguments shared by element tools:
+// ref, selector, xpath, text, label, placeholder, test_id, aria_role with
+// aria_name, exact, nth and within (a parent target object), plus
+// timeout_ms. The target may also be given as a "target" object, which is
+// how tools whose own arguments are in reserved (e.g. fill_ref's text) take
+// those kinds.
+func optionalTargetSpec(args map[string]interface{}, defaultTimeout int, reserved ...string) (TargetSpec, error) {
+	var spec TargetSpec
+	var err error
+	if raw, ok := args["target"]; ok && raw != nil {
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return spec, invalidArgf("expected object for 'target'")
+		}
+		if spec, err = parseTarget(obj, nil); err != nil {
+			return spec, err
+		}
+		if !spec.isSet() {
+			return spec, invalidArgf("'target' needs one of: %s", strings.Join(targetKeys, ", "))
+		}
+	} else if spec, err = parseTarget(args, reserved); err != nil {
+		return spec, err
 	}
+	if spec.Timeout, err = optionalInt(args, "timeout_ms", defaultTimeout); err != nil {
+		return spec, err
+	}
+	return spec, nil
+}
 
-	var locator playwright.Locator
-	if selector != "" {
-		locator = page.Locator(selector)
-	} else {
-		opts := playwright.PageGetByRoleOptions{}
-		if ariaName != "" {
-			opts.Name = ariaName
+func parseTarget(args map[string]interface{}, reserved []string) (TargetSpec, error) {
+	spec := TargetSpec{}
+	skip := map[string]bool{}
+	for _, key := range reserved {
+		skip[key] = true
+	}
+	dests := []*string{&spec.Ref, &spec.Selector, &spec.XPath, &spec.Text, &spec.Label, &spec.Placeholder, &spec.TestID, &spec.AriaRole}
+	given := []string{}
+	for i, key := range targetKeys {
+		if skip[key] {
+			continue
 		}
-		locator = page.GetByRole(playwright.AriaRole(ariaRole), opts)
+		value, err := optionalString(args, key, "")
+		if err != nil {
+			return spec, err
+		}
+		*dests[i] = value
+		if strings.TrimSpace(value) != "" {
+			given = append(given, key)
+		}
+	}
+	var err error
+	if spec.AriaName, err = optionalString(args, "aria_name", ""); err != nil {
+		return spec, err
+	}
+	if spec.Exact, err = optionalBool(args, "exact", false); err != nil {
+		return spec, err
+	}
+	if spec.Nth, err = optionalInt(args, "nth", 1); err != nil {
+		return spec, err
+	}
+	if raw, ok := args["within"]; ok && raw != nil {
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return spec, invalidArgf("expected object for 'within'")
+		}
+		parent, err := parseTarget(obj, nil)
+		if err != nil {
+			return spec, err
+		}
+		if !parent.isSet() {
+			return spec, invalidArgf("'within' needs one of: %s", strings.Join(targetKeys, ", "))
+		}
+		spec.Within = &parent
+	}
+
+	switch {
+	case len(given) > 1:
+		return spec, invalidArgf("a target takes one of %s (got %s)", strings.Join(targetKeys, ", "), strings.Join(given, ", "))
+	case strings.TrimSpace(spec.AriaName) != "" && strings.TrimSpace(spec.AriaRole) == "":
+		return spec, invalidArgf("aria_name requires aria_role")
+	case strings.TrimSpace(spec.Ref) != "" && spec.Within != nil:
+		return spec, invalidArgf("ref already names one element; it cannot be combined with within")
+	case spec.Within != nil && len(given) == 0:
+		return spec, invalidArgf("within needs a target to look for inside it")
 	}
+	return spec, nil
+}
+
+// actionTarget reads the element of click_ref and fill_ref: a snapshot ref,
+// or any other target for steps that must outlive refs (such as a locator
+// from snapshot with_locators).
+func actionTarget(args map[string]interface{}, reserved ...string) (TargetSpec, error) {
+	spec, err := optionalTargetSpec(args, 15_000, reserved...)
+	if err != nil {
+		return spec, err
+	}
+	if !spec.isSet() {
+		return spec, errTargetRequired()
+	}
+	return spec, nil
+}
+
+func errTargetRequired() error {
+	return invalidArgf("a target is required: one of %s", strings.Join(targetKeys, ", "))
+}
 
-	target := locator
-	nth := spec.effectiveNth()
-	if nth > 1 {
-		target = locator.Nth(nth - 1)
+// result starts a tool result that names the target the way it was given.
+func (s TargetSpec) result() RunResult {
+	if strings.TrimSpace(s.Ref) != "" {
+		return RunResult{"ref": s.Ref}
+	}
+	return RunResult{"target": s.describe()}
+}
+
+func resolveBounds(page playwright.Page, spec TargetSpec) (*playwright.Rect, error) {
+	if strings.TrimSpace(spec.Ref) != "" {
+		return resolveRefBounds(page, spec)
+	}
+
+	target, release, err := specLocator(page, spec)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
 	if err := target.WaitFor(playwright.LocatorWaitForOptions{
 		State:   playwright.WaitForSelectorStateVisible,
 		Timeout: playwright.Float(float64(spec.timeoutMs())),
 	}); err != nil {
-		return nil, fmt.Errorf("target not found or not visible (%s): %w", spec.describe(), err)
+		return nil, NewToolError(ErrNotVisible, fmt.Errorf("target not found or not visible (%s): %w", spec.describe(), err), map[string]interface{}{"target": spec.describe()})
 	}
 
 	box, err := target.BoundingBox()
@@ -84,7 +220,234 @@ func resolveBounds(page playwright.Page, spec TargetSpec) (*playwright.Rect, err
 		return nil, fmt.Errorf("failed to get bounds (%s): %w", spec.describe(), err)
 	}
 	if box == nil || box.Width <= 0 || box.Height <= 0 {
-		return nil, fmt.Errorf("element has no bounding box (%s)", spec.describe())
+		return nil, NewToolError(ErrNotVisible, fmt.Errorf("element has no bounding box (%s)", spec.describe()), map[string]interface{}{"target": spec.describe()})
+	}
+	return box, nil
+}
+
+// resolveElement returns a handle for the spec's element, waiting for it to
+// be attached. Callers must dispose it.
+func resolveElement(page playwright.Page, spec TargetSpec) (playwright.ElementHandle, error) {
+	if strings.TrimSpace(spec.Ref) != "" {
+		return SelectRef(page, spec.Ref, "simple")
+	}
+	locator, release, err := specLocator(page, spec)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := locator.WaitFor(playwright.LocatorWaitForOptions{
+		State:   playwright.WaitForSelectorStateAttached,
+		Timeout: playwright.Float(float64(spec.timeoutMs())),
+	}); err != nil {
+		if !errors.Is(err, playwright.ErrTimeout) {
+			return nil, err
+		}
+		return nil, errTargetNotFound(spec, err)
+	}
+	el, err := locator.ElementHandle()
+	if err != nil {
+		return nil, errTargetNotFound(spec, err)
+	}
+	return el, nil
+}
+
+// errTargetNotFound reports that no element matched spec within its
+// timeout.
+func errTargetNotFound(spec TargetSpec, err error) error {
+	return NewToolError(ErrNotFound, fmt.Errorf("target not found (%s): %w", spec.describe(), err), map[string]interface{}{"target": spec.describe()})
+}
+
+// runFind resolves a selector/aria target to a snapshot ref so later steps
+// can use ref-based tools on it.
+func runFind(page playwright.Page, args map[string]interface{}) (RunResult, error) {
+	spec, err := optionalTargetSpec(args, 5_000)
+	if err != nil {
+		return nil, err
+	}
+	if !spec.isSet() {
+		return nil, errTargetRequired()
+	}
+	if err := ensureInjected(page, "simple"); err != nil {
+		return nil, err
+	}
+	el, err := resolveElement(page, spec)
+	if err != nil {
+		return nil, err
+	}
+	defer el.Dispose()
+	raw, err := el.Evaluate("(el) => globalThis.__devBrowser_describeElement(el)")
+	if err != nil {
+		return nil, err
+	}
+	item, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected find result")
+	}
+	return RunResult{
+		"target": spec.describe(),
+		"ref":    item["ref"],
+		"role":   item["role"],
+		"name":   item["name"],
+		"line":   item["line"],
+	}, nil
+}
+
+// specLocator builds the Playwright locator for a target's nth match,
+// inside its parent when within is set. Callers must call release once they
+// are done with the locator; it undoes the marking a ref parent needs.
+func specLocator(page playwright.Page, spec TargetSpec) (playwright.Locator, func(), error) {
+	if strings.TrimSpace(spec.Ref) != "" {
+		return refLocator(page, spec.Ref)
+	}
+	locator, release, err := specMatches(page, spec)
+	if err != nil {
+		return nil, release, err
+	}
+	// Always narrow, nth=1 included, so a target matching several elements
+	// means the one describe names instead of a strict-mode error.
+	return locator.Nth(spec.effectiveNth() - 1), release, nil
+}
+
+// specMatches is specLocator without nth: a locator for every match, for
+// counting. spec must not be a ref.
+func specMatches(page playwright.Page, spec TargetSpec) (playwright.Locator, func(), error) {
+	if !spec.isSet() {
+		return nil, func() {}, errTargetRequired()
+	}
+	var parent playwright.Locator
+	release := func() {}
+	if spec.Within != nil {
+		var err error
+		if parent, release, err = specLocator(page, *spec.Within); err != nil {
+			return nil, release, err
+		}
+	}
+	exact := playwright.Bool(spec.Exact)
+
+	var locator playwright.Locator
+	switch {
+	case strings.TrimSpace(spec.Selector) != "":
+		if parent != nil {
+			locator = parent.Locator(spec.Selector)
+		} else {
+			locator = page.Locator(spec.Selector)
+		}
+	case strings.TrimSpace(spec.XPath) != "":
+		if parent != nil {
+			locator = parent.Locator("xpath=" + spec.XPath)
+		} else {
+			locator = page.Locator("xpath=" + spec.XPath)
+		}
+	case strings.TrimSpace(spec.Text) != "":
+		if parent != nil {
+			locator = parent.GetByText(spec.Text, playwright.LocatorGetByTextOptions{Exact: exact})
+		} else {
+			locator = page.GetByText(spec.Text, playwright.PageGetByTextOptions{Exact: exact})
+		}
+	case strings.TrimSpace(spec.Label) != "":
+		if parent != nil {
+			locator = parent.GetByLabel(spec.Label, playwright.LocatorGetByLabelOptions{Exact: exact})
+		} else {
+			locator = page.GetByLabel(spec.Label, playwright.PageGetByLabelOptions{Exact: exact})
+		}
+	case strings.TrimSpace(spec.Placeholder) != "":
+		if parent != nil {
+			locator = parent.GetByPlaceholder(spec.Placeholder, playwright.LocatorGetByPlaceholderOptions{Exact: exact})
+		} else {
+			locator = page.GetByPlaceholder(spec.Placeholder, playwright.PageGetByPlaceholderOptions{Exact: exact})
+		}
+	case strings.TrimSpace(spec.TestID) != "":
+		if parent != nil {
+			locator = parent.GetByTestId(spec.TestID)
+		} else {
+			locator = page.GetByTestId(spec.TestID)
+		}
+	default:
+		role := playwright.AriaRole(strings.TrimSpace(spec.AriaRole))
+		var name interface{}
+		if strings.TrimSpace(spec.AriaName) != "" {
+			name = spec.AriaName
+		}
+		if parent != nil {
+			locator = parent.GetByRole(role, playwright.LocatorGetByRoleOptions{Name: name, Exact: exact})
+		} else {
+			locator = page.GetByRole(role, playwright.PageGetByRoleOptions{Name: name, Exact: exact})
+		}
+	}
+	return locator, release, nil
+}
+
+var scopeSeq atomic.Int64
+
+// refLocator turns a ref into a locator (for use as a within parent).
+// Locators cannot be scoped to an element handle, so the element is marked
+// with an attribute unique to this call; release removes it again.
+func refLocator(page playwright.Page, ref string) (playwright.Locator, func(), error) {
+	el, err := SelectRef(page, ref, "simple")
+	if err != nil {
+		return nil, func() {}, err
+	}
+	token := fmt.Sprintf("%s-%d-%d", ref, NowMS(), scopeSeq.Add(1))
+	if _, err := el.Evaluate("(el, token) => el.setAttribute('data-dev-browser-scope', token)", token); err != nil {
+		_ = el.Dispose()
+		return nil, func() {}, err
+	}
+	release := func() {
+		_, _ = el.Evaluate("(el) => el.removeAttribute('data-dev-browser-scope')")
+		_ = el.Dispose()
+	}
+	return page.Locator(fmt.Sprintf("[data-dev-browser-scope=%q]", token)), release, nil
+}
+
+// refPosition is the 0-based index of ref's element among the elements
+// locator matches, or -1 when it is not one of them, and how many it
+// matches. Generated locators are checked with it because the snapshot's
+// names only approximate Playwright's accessible names.
+func refPosition(locator playwright.Locator, ref string) (int, int, error) {
+	raw, err := locator.EvaluateAll(`(els, ref) => {
+  const el = (globalThis.__devBrowserRefs || {})[ref];
+  return [el ? els.indexOf(el) : -1, els.length];
+}`, ref)
+	if err != nil {
+		return -1, 0, err
+	}
+	var pos []int
+	if err := decodeJSResult(raw, &pos); err != nil || len(pos) != 2 {
+		return -1, 0, fmt.Errorf("unexpected position result: %v", raw)
+	}
+	return pos[0], pos[1], nil
+}
+
+// roleRefPosition is refPosition for the exact role and name locator the
+// role targets in recordings and exports resolve to.
+func roleRefPosition(page playwright.Page, role, name, ref string) (int, error) {
+	locator, release, err := specMatches(page, TargetSpec{AriaRole: role, AriaName: name, Exact: true})
+	defer release()
+	if err != nil {
+		return -1, err
+	}
+	index, _, err := refPosition(locator, ref)
+	return index, err
+}
+
+func resolveRefBounds(page playwright.Page, spec TargetSpec) (*playwright.Rect, error) {
+	el, err := SelectRef(page, spec.Ref, "simple")
+	if err != nil {
+		return nil, err
+	}
+	defer el.Dispose()
+	if err := el.WaitForElementState(*playwright.ElementStateVisible, playwright.ElementHandleWaitForElementStateOptions{
+		Timeout: playwright.Float(float64(spec.timeoutMs())),
+	}); err != nil {
+		return nil, NewToolError(ErrNotVisible, fmt.Errorf("target not visible (%s): %w", spec.describe(), err), map[string]interface{}{"target": spec.describe()})
+	}
+	box, err := el.BoundingBox()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bounds (%s): %w", spec.describe(), err)
+	}
+	if box == nil || box.Width <= 0 || box.Height <= 0 {
+		return nil, NewToolError(ErrNotVisible, fmt.Errorf("element has no bounding box (%s)", spec.describe()), map[string]interface{}{"target": spec.describe()})
 	}
 	return box, nil
 }
@@ -105,7 +468,7 @@ func viewportSize(page playwright.Page) playwright.Size {
 
 func clipWithPadding(box *playwright.Rect, padding int, viewport playwright.Size) (*playwright.Rect, error) {
 	if padding < 0 {
-		return nil, fmt.Errorf("padding_px must be non-negative")
+		return nil, invalidArgf("padding_px must be non-negative")
 	}
 	vw := float64(viewport.Width)
 	vh := float64(viewport.Height)
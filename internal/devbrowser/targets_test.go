@@ -0,0 +1,139 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+func TestActionTarget(t *testing.T) {
+	spec, err := actionTarget(map[string]interface{}{"ref": "e4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.timeoutMs() != 15_000 {
+		t.Fatalf("timeout = %d, want 15000", spec.timeoutMs())
+	}
+	if res := spec.result(); res["ref"] != "e4" {
+		t.Fatalf("result = %v", res)
+	}
+
+	spec, err = actionTarget(map[string]interface{}{"selector": `role=button[name="Save"]`, "timeout_ms": float64(2000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.timeoutMs() != 2000 {
+		t.Fatalf("timeout = %d, want 2000", spec.timeoutMs())
+	}
+	if res := spec.result(); res["target"] != `selector="role=button[name=\"Save\"]" nth=1` {
+		t.Fatalf("result = %v", res)
+	}
+
+	if _, err := actionTarget(map[string]interface{}{"text": "x"}, "text"); err == nil {
+		t.Fatal("expected error when text is the tool's own argument")
+	}
+	spec, err = actionTarget(map[string]interface{}{"text": "hello", "target": map[string]interface{}{"label": "Email"}}, "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Label != "Email" || spec.Text != "" {
+		t.Fatalf("spec = %+v", spec)
+	}
+}
+
+func TestOptionalTargetSpecKinds(t *testing.T) {
+	spec, err := optionalTargetSpec(map[string]interface{}{
+		"text":   "Delete",
+		"exact":  true,
+		"nth":    float64(2),
+		"within": map[string]interface{}{"aria_role": "dialog", "aria_name": "Confirm"},
+	}, 5_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `text="Delete" exact nth=2 within (aria_role="dialog" aria_name="Confirm" nth=1)`
+	if got := spec.describe(); got != want {
+		t.Fatalf("describe = %s, want %s", got, want)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"selector": "#a", "xpath": "//a"},
+		{"aria_name": "Save"},
+		{"ref": "e1", "within": map[string]interface{}{"selector": "form"}},
+		{"within": map[string]interface{}{"selector": "form"}},
+		{"within": map[string]interface{}{}},
+		{"target": "e1"},
+	} {
+		if _, err := optionalTargetSpec(args, 5_000); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+
+	spec, err = optionalTargetSpec(map[string]interface{}{"test_id": "save", "within": map[string]interface{}{"ref": "e3"}}, 5_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spec.describe(); got != `test_id="save" nth=1 within (ref="e3")` {
+		t.Fatalf("describe = %s", got)
+	}
+}
+
+// locatorAPI lets chainLocator embed playwright.Locator under a name that
+// does not clash with its Locator method.
+type locatorAPI = playwright.Locator
+
+// chainLocator records the calls that built it, e.g. `sel(.row).nth(0)`.
+type chainLocator struct {
+	locatorAPI
+	chain string
+}
+
+func (l *chainLocator) Locator(selector interface{}, options ...playwright.LocatorLocatorOptions) playwright.Locator {
+	return &chainLocator{chain: fmt.Sprintf("%s.sel(%v)", l.chain, selector)}
+}
+
+func (l *chainLocator) Nth(index int) playwright.Locator {
+	return &chainLocator{chain: l.chain + ".nth(" + strconv.Itoa(index) + ")"}
+}
+
+type chainPage struct {
+	playwright.Page
+}
+
+func (p *chainPage) Locator(selector string, options ...playwright.PageLocatorOptions) playwright.Locator {
+	return &chainLocator{chain: "sel(" + selector + ")"}
+}
+
+func TestSpecLocatorAlwaysNarrowsToNth(t *testing.T) {
+	page := &chainPage{}
+	cases := []struct {
+		spec TargetSpec
+		want string
+	}{
+		{TargetSpec{Selector: ".feed"}, "sel(.feed).nth(0)"},
+		{TargetSpec{Selector: ".feed", Nth: 1}, "sel(.feed).nth(0)"},
+		{TargetSpec{Selector: ".feed", Nth: 3}, "sel(.feed).nth(2)"},
+		{TargetSpec{Selector: ".row", Within: &TargetSpec{Selector: ".dialog", Nth: 2}}, "sel(.dialog).nth(1).sel(.row).nth(0)"},
+	}
+	for _, tc := range cases {
+		locator, release, err := specLocator(page, tc.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+		if got := locator.(*chainLocator).chain; got != tc.want {
+			t.Fatalf("%s: locator = %s, want %s", tc.spec.describe(), got, tc.want)
+		}
+	}
+
+	locator, release, err := specMatches(page, TargetSpec{Selector: ".feed", Nth: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if got := locator.(*chainLocator).chain; got != "sel(.feed)" {
+		t.Fatalf("specMatches should not narrow, got %s", got)
+	}
+}
@@ -0,0 +1,204 @@
+package devbrowser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EstimateTokens approximates how many LLM tokens text costs. Words cost one
+// token per ~4 characters, punctuation and symbols cost one token each and
+// non-Latin runes are counted individually.
+func EstimateTokens(text string) int {
+	tokens := 0
+	word := 0
+	flush := func() {
+		if word > 0 {
+			tokens += (word + 3) / 4
+			word = 0
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word++
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flush()
+			tokens++
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// truncateToTokens cuts text on line boundaries so it fits maxTokens,
+// including the truncation marker.
+func truncateToTokens(text string, maxTokens int) string {
+	if maxTokens <= 0 || EstimateTokens(text) <= maxTokens {
+		return text
+	}
+	marker := fmt.Sprintf("- [...] truncated (max_tokens=%d)", maxTokens)
+	budget := maxTokens - EstimateTokens(marker)
+	lines := strings.Split(text, "\n")
+	kept := []string{}
+	used := 0
+	for _, line := range lines {
+		cost := EstimateTokens(line)
+		if used+cost > budget {
+			break
+		}
+		kept = append(kept, line)
+		used += cost
+	}
+	kept = append(kept, marker)
+	return strings.Join(kept, "\n")
+}
+
+// clipToTokens cuts text at a rune boundary so it fits maxTokens, for text
+// such as HTML whose lines can each be longer than the budget. The second
+// result reports whether anything was cut.
+func clipToTokens(text string, maxTokens int) (string, bool) {
+	if maxTokens <= 0 || EstimateTokens(text) <= maxTokens {
+		return text, false
+	}
+	const marker = "…"
+	budget := maxTokens - EstimateTokens(marker)
+	runes := []rune(text)
+	n := sort.Search(len(runes)+1, func(i int) bool {
+		return EstimateTokens(string(runes[:i])) > budget
+	}) - 1
+	if n < 0 {
+		n = 0
+	}
+	return string(runes[:n]) + marker, true
+}
+
+// jsonTokens estimates the tokens v costs once encoded as JSON.
+func jsonTokens(v interface{}) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return EstimateTokens(string(data))
+}
+
+// countWithinTokens returns how many of n items, taken in order, fit in
+// maxTokens when item i costs cost(i). maxTokens <= 0 keeps all of them.
+func countWithinTokens(n, maxTokens int, cost func(i int) int) int {
+	if maxTokens <= 0 {
+		return n
+	}
+	used := 0
+	for i := 0; i < n; i++ {
+		used += cost(i)
+		if used > maxTokens {
+			return i
+		}
+	}
+	return n
+}
+
+// snapshotDropOrder returns item indexes in the order they should be dropped
+// to save tokens: repeated role/name pairs first, then unnamed items, then
+// links, then everything else. Within a tier later items go first, since
+// footers and sidebars tend to trail the main content.
+func snapshotDropOrder(items []map[string]interface{}) []int {
+	seen := map[string]bool{}
+	tiers := make([]int, len(items))
+	for i, item := range items {
+		role, _ := item["role"].(string)
+		name, _ := item["name"].(string)
+		key := role + "\x00" + name
+		switch {
+		case seen[key]:
+			tiers[i] = 0
+		case strings.TrimSpace(name) == "":
+			tiers[i] = 1
+		case role == "link":
+			tiers[i] = 2
+		default:
+			tiers[i] = 3
+		}
+		seen[key] = true
+	}
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ta, tb := tiers[order[a]], tiers[order[b]]
+		if ta != tb {
+			return ta < tb
+		}
+		return order[a] > order[b]
+	})
+	return order
+}
+
+// fitItemsToTokens drops the fewest low-value items needed for render(items)
+// to fit maxTokens. It returns the kept items, their rendering and how many
+// items were dropped. If nothing fits, the text is truncated by lines.
+func fitItemsToTokens(items []map[string]interface{}, maxTokens int, render func([]map[string]interface{}) (string, error)) ([]map[string]interface{}, string, int, error) {
+	text, err := render(items)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if maxTokens <= 0 || EstimateTokens(text) <= maxTokens {
+		return items, text, 0, nil
+	}
+
+	order := snapshotDropOrder(items)
+	keep := func(drop int) []map[string]interface{} {
+		dropped := make(map[int]bool, drop)
+		for _, idx := range order[:drop] {
+			dropped[idx] = true
+		}
+		out := make([]map[string]interface{}, 0, len(items)-drop)
+		for i, item := range items {
+			if !dropped[i] {
+				out = append(out, item)
+			}
+		}
+		return out
+	}
+	withMarker := func(text string, drop int) string {
+		if drop == 0 {
+			return text
+		}
+		marker := fmt.Sprintf("- [...] %d low-value items omitted (max_tokens=%d)", drop, maxTokens)
+		if text == "" {
+			return marker
+		}
+		return text + "\n" + marker
+	}
+
+	lo, hi := 1, len(items)
+	best := -1
+	bestText := ""
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		candidate, err := render(keep(mid))
+		if err != nil {
+			return nil, "", 0, err
+		}
+		candidate = withMarker(candidate, mid)
+		if EstimateTokens(candidate) <= maxTokens {
+			best, bestText = mid, candidate
+			hi = mid - 1
+		} else {
+			lo = mid + 1
+		}
+	}
+	if best < 0 {
+		return items, truncateToTokens(text, maxTokens), 0, nil
+	}
+	return keep(best), bestText, best, nil
+}
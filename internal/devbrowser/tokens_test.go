@@ -0,0 +1,133 @@
+package devbrowser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"", 0},
+		{"   ", 0},
+		{"ok", 1},
+		{"button", 2},
+		{`- button name="Save" [ref=e1]`, 13},
+		{"日本語", 3},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.input); got != tt.expected {
+			t.Fatalf("EstimateTokens(%q) = %d, expected %d", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestTruncateToTokens(t *testing.T) {
+	lines := []string{}
+	for i := 0; i < 50; i++ {
+		lines = append(lines, fmt.Sprintf("- link name=\"Item %d\" [ref=e%d]", i, i))
+	}
+	text := strings.Join(lines, "\n")
+	out := truncateToTokens(text, 60)
+	if EstimateTokens(out) > 60 {
+		t.Fatalf("expected <= 60 tokens, got %d", EstimateTokens(out))
+	}
+	if !strings.HasSuffix(out, "truncated (max_tokens=60)") {
+		t.Fatalf("expected truncation marker, got %q", out)
+	}
+	if !strings.HasPrefix(out, lines[0]) {
+		t.Fatalf("expected leading lines to be kept, got %q", out)
+	}
+	if truncateToTokens("short", 60) != "short" {
+		t.Fatalf("expected short text unchanged")
+	}
+}
+
+func TestSnapshotDropOrder(t *testing.T) {
+	items := []map[string]interface{}{
+		{"ref": "e1", "role": "button", "name": "Save"},
+		{"ref": "e2", "role": "link", "name": "Docs"},
+		{"ref": "e3", "role": "textbox", "name": nil},
+		{"ref": "e4", "role": "link", "name": "Docs"},
+		{"ref": "e5", "role": "link", "name": "Privacy"},
+	}
+	order := snapshotDropOrder(items)
+	expected := []int{3, 2, 4, 1, 0}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected order %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestFitItemsToTokens(t *testing.T) {
+	items := []map[string]interface{}{
+		{"role": "heading", "name": "Checkout"},
+		{"role": "textbox", "name": "Card number"},
+		{"role": "button", "name": "Pay now"},
+	}
+	for i := 0; i < 20; i++ {
+		items = append(items, map[string]interface{}{"role": "link", "name": "Footer link"})
+	}
+	render := func(in []map[string]interface{}) (string, error) {
+		lines := []string{}
+		for _, item := range in {
+			lines = append(lines, fmt.Sprintf("- %s name=%q", item["role"], item["name"]))
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	kept, text, dropped, err := fitItemsToTokens(items, 60, render)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if EstimateTokens(text) > 60 {
+		t.Fatalf("expected <= 60 tokens, got %d: %q", EstimateTokens(text), text)
+	}
+	if dropped == 0 || len(kept) != len(items)-dropped {
+		t.Fatalf("unexpected dropped=%d kept=%d", dropped, len(kept))
+	}
+	for _, name := range []string{"Checkout", "Card number", "Pay now"} {
+		if !strings.Contains(text, name) {
+			t.Fatalf("expected main content %q to survive, got %q", name, text)
+		}
+	}
+
+	kept, _, dropped, err = fitItemsToTokens(items, 0, render)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dropped != 0 || len(kept) != len(items) {
+		t.Fatalf("expected no budget to keep everything")
+	}
+}
+
+func TestClipToTokens(t *testing.T) {
+	html := `<div class="card"><button type="submit">` + strings.Repeat("Save changes ", 40) + `</button></div>`
+	out, cut := clipToTokens(html, 30)
+	if !cut || EstimateTokens(out) > 30 || !strings.HasSuffix(out, "…") {
+		t.Fatalf("clip = %q (%d tokens, cut=%v)", out, EstimateTokens(out), cut)
+	}
+	if !strings.HasPrefix(html, strings.TrimSuffix(out, "…")) {
+		t.Fatalf("expected a prefix of the input, got %q", out)
+	}
+	if out, cut := clipToTokens("<p>ok</p>", 30); cut || out != "<p>ok</p>" {
+		t.Fatalf("short text changed: %q", out)
+	}
+}
+
+func TestCountWithinTokens(t *testing.T) {
+	cost := func(int) int { return 10 }
+	if got := countWithinTokens(5, 35, cost); got != 3 {
+		t.Fatalf("count = %d, expected 3", got)
+	}
+	if got := countWithinTokens(5, 0, cost); got != 5 {
+		t.Fatalf("unlimited count = %d, expected 5", got)
+	}
+	if got := countWithinTokens(5, 5, cost); got != 0 {
+		t.Fatalf("tiny budget count = %d, expected 0", got)
+	}
+}
@@ -0,0 +1,510 @@
+package devbrowser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// WaitCondition is a single non-load-state condition for the wait tool.
+type WaitCondition struct {
+	Kind     string
+	Target   TargetSpec
+	State    string
+	Text     string
+	URL      *regexp.Regexp
+	Function string
+	Console  *regexp.Regexp
+	Response []linkPattern
+	Status   *statusExpectation
+	// Since skips console entries up to this id; SinceMS skips responses
+	// that finished before this time (epoch ms).
+	Since     int64
+	SinceMS   int64
+	TimeoutMs int
+}
+
+var elementWaitStates = map[string]bool{
+	"visible": true, "hidden": true, "attached": true, "detached": true,
+	"enabled": true, "disabled": true, "editable": true,
+}
+
+// parseWaitCondition reads the condition arguments of the wait tool. It
+// returns nil when none is given, so the caller falls back to load states.
+func parseWaitCondition(args map[string]interface{}) (*WaitCondition, error) {
+	spec, err := optionalTargetSpec(args, 10_000, "text")
+	if err != nil {
+		return nil, err
+	}
+	cond := &WaitCondition{Target: spec, TimeoutMs: spec.timeoutMs()}
+	kinds := []string{}
+	if spec.isSet() {
+		kinds = append(kinds, "element")
+	}
+
+	if cond.Text, err = optionalString(args, "text", ""); err != nil {
+		return nil, err
+	}
+	if cond.Text != "" {
+		kinds = append(kinds, "text")
+	}
+	urlPattern, err := optionalString(args, "url_matches", "")
+	if err != nil {
+		return nil, err
+	}
+	if urlPattern != "" {
+		if cond.URL, err = regexp.Compile(urlPattern); err != nil {
+			return nil, invalidArgf("invalid url_matches: %v", err)
+		}
+		kinds = append(kinds, "url")
+	}
+	if cond.Function, err = optionalString(args, "function", ""); err != nil {
+		return nil, err
+	}
+	if cond.Function != "" {
+		kinds = append(kinds, "function")
+	}
+	consolePattern, err := optionalString(args, "console_match", "")
+	if err != nil {
+		return nil, err
+	}
+	if consolePattern != "" {
+		if cond.Console, err = regexp.Compile(consolePattern); err != nil {
+			return nil, invalidArgf("invalid console_match: %v", err)
+		}
+		kinds = append(kinds, "console")
+	}
+	responsePattern, err := optionalString(args, "response", "")
+	if err != nil {
+		return nil, err
+	}
+	if responsePattern != "" {
+		if cond.Response, err = compileLinkPatterns([]string{responsePattern}); err != nil {
+			return nil, err
+		}
+		kinds = append(kinds, "response")
+	}
+	since, err := optionalInt(args, "since", 0)
+	if err != nil {
+		return nil, err
+	}
+	if since < 0 {
+		return nil, invalidArgf("since must be >= 0")
+	}
+	if since > 0 && consolePattern == "" {
+		return nil, invalidArgf("since requires console_match")
+	}
+	cond.Since = int64(since)
+	sinceMS, err := optionalInt(args, "since_ms", 0)
+	if err != nil {
+		return nil, err
+	}
+	if sinceMS < 0 {
+		return nil, invalidArgf("since_ms must be >= 0")
+	}
+	if sinceMS > 0 && responsePattern == "" {
+		return nil, invalidArgf("since_ms requires response")
+	}
+	cond.SinceMS = int64(sinceMS)
+	if raw, ok := args["status"]; ok && raw != nil {
+		if responsePattern == "" {
+			return nil, invalidArgf("status requires response")
+		}
+		if cond.Status, err = parseStatusExpectation(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	switch len(kinds) {
+	case 0:
+		return nil, nil
+	case 1:
+		cond.Kind = kinds[0]
+	default:
+		return nil, invalidArgf("wait takes one condition, got %s", strings.Join(kinds, ", "))
+	}
+
+	if cond.Kind == "element" {
+		state, err := optionalString(args, "state", "visible")
+		if err != nil {
+			return nil, err
+		}
+		cond.State = strings.ToLower(state)
+		if !elementWaitStates[cond.State] {
+			return nil, invalidArgf("invalid state '%s' (expected one of: visible, hidden, attached, detached, enabled, disabled, editable)", state)
+		}
+	}
+	return cond, nil
+}
+
+// waitForCondition blocks until cond holds. Like the load-state strategies it
+// reports a timeout as ok=false rather than an error. Console and response
+// conditions are read from the daemon's record when events is set, so a
+// message or response that came before this call is seen too.
+func waitForCondition(page playwright.Page, cond *WaitCondition, events PageEvents) (RunResult, error) {
+	start := time.Now()
+	timeout := playwright.Float(float64(cond.TimeoutMs))
+
+	var matched map[string]interface{}
+	var err error
+	switch cond.Kind {
+	case "element":
+		matched, err = waitForElementState(page, cond)
+	case "text":
+		err = page.GetByText(cond.Text).First().WaitFor(playwright.LocatorWaitForOptions{
+			State:   playwright.WaitForSelectorStateVisible,
+			Timeout: timeout,
+		})
+		matched = map[string]interface{}{"text": cond.Text}
+	case "url":
+		matched, err = waitForURLMatch(page, cond.URL, cond.TimeoutMs)
+	case "function":
+		var handle playwright.JSHandle
+		handle, err = page.WaitForFunction(cond.Function, nil, playwright.PageWaitForFunctionOptions{Timeout: timeout})
+		if err == nil {
+			value, _ := handle.JSONValue()
+			_ = handle.Dispose()
+			matched = map[string]interface{}{"value": value}
+		}
+	case "console":
+		if events != nil {
+			matched, err = waitForRecordedConsole(page, events, cond)
+			break
+		}
+		var raw interface{}
+		raw, err = page.WaitForEvent("console", playwright.PageWaitForEventOptions{
+			Predicate: func(msg playwright.ConsoleMessage) bool {
+				return cond.Console.MatchString(msg.Text())
+			},
+			Timeout: timeout,
+		})
+		if msg, ok := raw.(playwright.ConsoleMessage); ok && err == nil {
+			matched = map[string]interface{}{"type": msg.Type(), "text": msg.Text()}
+		}
+	case "response":
+		if events != nil {
+			matched, err = waitForRecordedResponse(page, events, cond)
+			break
+		}
+		var raw interface{}
+		raw, err = page.WaitForEvent("response", playwright.PageWaitForEventOptions{
+			Predicate: func(resp playwright.Response) bool {
+				if !matchAnyLinkPattern(cond.Response, resp.URL()) {
+					return false
+				}
+				return cond.Status == nil || cond.Status.matches(resp.Status())
+			},
+			Timeout: timeout,
+		})
+		if resp, ok := raw.(playwright.Response); ok && err == nil {
+			matched = map[string]interface{}{
+				"url":    resp.URL(),
+				"status": resp.Status(),
+				"method": resp.Request().Method(),
+			}
+		}
+	}
+
+	timedOut := isTimeout(err)
+	if err != nil && !timedOut {
+		return nil, err
+	}
+	res := RunResult{
+		"ok":        !timedOut,
+		"condition": cond.Kind,
+		"timed_out": timedOut,
+		"waited_ms": int(time.Since(start).Milliseconds()),
+	}
+	if !timedOut {
+		res["matched"] = matched
+	}
+	return res, nil
+}
+
+// waitForRecordedConsole polls the daemon's console log for an entry after
+// cond.Since that matches cond.Console.
+func waitForRecordedConsole(page playwright.Page, events PageEvents, cond *WaitCondition) (map[string]interface{}, error) {
+	since := cond.Since
+	deadline := time.Now().Add(time.Duration(cond.TimeoutMs) * time.Millisecond)
+	for {
+		logs, lastID, err := events.ConsoleSince(since, "all", 0)
+		if err != nil {
+			return nil, err
+		}
+		if entry, ok := firstConsoleMatch(logs, cond.Console); ok {
+			return map[string]interface{}{"id": entry.ID, "type": entry.Type, "text": entry.Text}, nil
+		}
+		if lastID > since {
+			since = lastID
+		}
+		if !time.Now().Before(deadline) {
+			return nil, NewToolError(ErrTimeout, fmt.Errorf("no console message matching %q after %dms", cond.Console.String(), cond.TimeoutMs), nil)
+		}
+		page.WaitForTimeout(100)
+	}
+}
+
+func firstConsoleMatch(logs []ConsoleEntry, re *regexp.Regexp) (ConsoleEntry, bool) {
+	for _, entry := range logs {
+		if re.MatchString(entry.Text) {
+			return entry, true
+		}
+	}
+	return ConsoleEntry{}, false
+}
+
+// waitForRecordedResponse polls the daemon's finished requests for a
+// response matching cond that ended at or after cond.SinceMS.
+func waitForRecordedResponse(page playwright.Page, events PageEvents, cond *WaitCondition) (map[string]interface{}, error) {
+	deadline := time.Now().Add(time.Duration(cond.TimeoutMs) * time.Millisecond)
+	for {
+		act, err := events.NetworkActivity()
+		if err != nil {
+			return nil, err
+		}
+		if req, ok := firstResponseMatch(act.Recent, cond); ok {
+			return map[string]interface{}{"url": req.URL, "status": req.Status, "method": req.Method, "ended_ms": req.EndedMS}, nil
+		}
+		if !time.Now().Before(deadline) {
+			return nil, NewToolError(ErrTimeout, fmt.Errorf("no response matching the pattern after %dms", cond.TimeoutMs), nil)
+		}
+		page.WaitForTimeout(100)
+	}
+}
+
+// firstResponseMatch returns the earliest recorded response that matches
+// cond. Failed requests and entries without a status never match.
+func firstResponseMatch(recent []NetworkRequest, cond *WaitCondition) (NetworkRequest, bool) {
+	for _, req := range recent {
+		if req.Failed || req.Status == 0 || req.EndedMS < cond.SinceMS {
+			continue
+		}
+		if !matchAnyLinkPattern(cond.Response, req.URL) {
+			continue
+		}
+		if cond.Status == nil || cond.Status.matches(req.Status) {
+			return req, true
+		}
+	}
+	return NetworkRequest{}, false
+}
+
+func waitForElementState(page playwright.Page, cond *WaitCondition) (map[string]interface{}, error) {
+	spec := cond.Target
+	matched := map[string]interface{}{"target": spec.describe(), "state": cond.State}
+	timeout := playwright.Float(float64(cond.TimeoutMs))
+
+	var el playwright.ElementHandle
+	if strings.TrimSpace(spec.Ref) != "" {
+		handle, err := SelectRef(page, spec.Ref, "simple")
+		if err != nil {
+			if staleRefSatisfies(cond.State, err) {
+				return matched, nil
+			}
+			return nil, err
+		}
+		el = handle
+	} else {
+		locator, release, err := specLocator(page, spec)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		switch cond.State {
+		case "visible", "hidden", "attached", "detached":
+			state := playwright.WaitForSelectorState(cond.State)
+			return matched, locator.WaitFor(playwright.LocatorWaitForOptions{
+				State:   &state,
+				Timeout: timeout,
+			})
+		}
+		if err := locator.WaitFor(playwright.LocatorWaitForOptions{
+			State:   playwright.WaitForSelectorStateAttached,
+			Timeout: timeout,
+		}); err != nil {
+			return nil, err
+		}
+		if el, err = locator.ElementHandle(); err != nil {
+			return nil, err
+		}
+	}
+	defer el.Dispose()
+
+	switch cond.State {
+	case "attached":
+		return matched, nil
+	case "detached":
+		deadline := time.Now().Add(time.Duration(cond.TimeoutMs) * time.Millisecond)
+		for {
+			connected, err := el.Evaluate("(el) => el.isConnected")
+			if err != nil || connected != true {
+				return matched, nil
+			}
+			if time.Now().After(deadline) {
+				return nil, NewToolError(ErrTimeout, fmt.Errorf("timeout %dms exceeded waiting for %s to detach", cond.TimeoutMs, spec.describe()), nil)
+			}
+			page.WaitForTimeout(100)
+		}
+	}
+	return matched, el.WaitForElementState(playwright.ElementState(cond.State), playwright.ElementHandleWaitForElementStateOptions{Timeout: timeout})
+}
+
+// staleRefSatisfies reports whether a failed ref lookup already means the
+// element is hidden or detached. Only a ref whose element left the DOM
+// does; an unknown ref or any other failure is an error.
+func staleRefSatisfies(state string, err error) bool {
+	if state != "detached" && state != "hidden" {
+		return false
+	}
+	var te *ToolError
+	return errors.As(err, &te) && te.Code == ErrRefStale
+}
+
+// waitForURLMatch polls rather than waiting for a navigation event so that
+// client-side route changes (pushState) are seen too.
+func waitForURLMatch(page playwright.Page, re *regexp.Regexp, timeoutMs int) (map[string]interface{}, error) {
+	deadline := time.Now().Add(time.Duration(timeoutMs) * time.Millisecond)
+	for {
+		if url := page.URL(); re.MatchString(url) {
+			return map[string]interface{}{"url": url}, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, NewToolError(ErrTimeout, fmt.Errorf("timeout %dms exceeded waiting for url to match %s", timeoutMs, re), nil)
+		}
+		page.WaitForTimeout(100)
+	}
+}
+
+// stableJS resolves once the DOM has not mutated and no visible box has
+// moved or resized for opts.frames consecutive animation frames, or when
+// opts.timeoutMs runs out. On timeout it reports what was still changing in
+// the last unstable frame.
+const stableJS = `async (opts) => {
+  const describe = (node) => {
+    let el = node && node.nodeType === 1 ? node : node && node.parentElement;
+    if (!el) return "";
+    let out = el.tagName.toLowerCase();
+    if (el.id) return out + "#" + el.id;
+    if (el.classList && el.classList.length) out += "." + Array.from(el.classList).slice(0, 2).join(".");
+    return out;
+  };
+
+  let mutations = 0;
+  let frameMutations = new Map();
+  const observer = new MutationObserver((records) => {
+    for (const r of records) {
+      mutations++;
+      const key = describe(r.target);
+      frameMutations.set(key, (frameMutations.get(key) || 0) + 1);
+    }
+  });
+  observer.observe(document.documentElement, { subtree: true, childList: true, attributes: true, characterData: true });
+
+  const boxes = () => {
+    const out = new Map();
+    const vw = innerWidth;
+    const vh = innerHeight;
+    for (const el of document.querySelectorAll("body *")) {
+      if (out.size >= opts.maxElements) break;
+      const r = el.getBoundingClientRect();
+      if (r.width === 0 && r.height === 0) continue;
+      if (r.bottom < 0 || r.right < 0 || r.top > vh || r.left > vw) continue;
+      out.set(el, r);
+    }
+    return out;
+  };
+  const moved = (a, b) => Math.abs(a.x - b.x) > 0.5 || Math.abs(a.y - b.y) > 0.5 ||
+    Math.abs(a.width - b.width) > 0.5 || Math.abs(a.height - b.height) > 0.5;
+  // rAF is throttled in background tabs; the timer keeps the loop going.
+  const nextFrame = () => new Promise((resolve) => {
+    let done = false;
+    const finish = () => { if (!done) { done = true; resolve(); } };
+    requestAnimationFrame(finish);
+    setTimeout(finish, 100);
+  });
+
+  const deadline = performance.now() + opts.timeoutMs;
+  let prev = boxes();
+  let quiet = 0;
+  let lastMutating = [];
+  let lastShifting = [];
+  try {
+    while (quiet < opts.frames && performance.now() < deadline) {
+      await nextFrame();
+      const cur = boxes();
+      const shifting = [];
+      for (const [el, r] of cur) {
+        const before = prev.get(el);
+        if (before && moved(before, r)) shifting.push(describe(el));
+      }
+      if (frameMutations.size === 0 && shifting.length === 0) {
+        quiet++;
+      } else {
+        quiet = 0;
+        lastMutating = Array.from(frameMutations, ([target, count]) => ({ target, count }))
+          .sort((a, b) => b.count - a.count)
+          .slice(0, 10);
+        lastShifting = Array.from(new Set(shifting)).slice(0, 10);
+      }
+      frameMutations = new Map();
+      prev = cur;
+    }
+  } finally {
+    observer.disconnect();
+  }
+
+  const running = typeof document.getAnimations === "function"
+    ? document.getAnimations().filter((a) => a.playState === "running").length
+    : 0;
+  return {
+    stable: quiet >= opts.frames,
+    quiet_frames: quiet,
+    mutations,
+    mutating: lastMutating,
+    shifting: lastShifting,
+    running_animations: running,
+  };
+}`
+
+func waitStable(page playwright.Page, frames int, timeoutMs int, minWaitMs int) (RunResult, error) {
+	if frames < 1 {
+		frames = 1
+	}
+	start := time.Now()
+	if minWaitMs > 0 {
+		page.WaitForTimeout(float64(minWaitMs))
+	}
+	raw, err := page.Evaluate(stableJS, map[string]interface{}{
+		"frames":      frames,
+		"timeoutMs":   timeoutMs,
+		"maxElements": 2_000,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("unexpected stable wait result")
+	}
+	stable, _ := m["stable"].(bool)
+	res := RunResult{
+		"ok":            stable,
+		"strategy":      "stable",
+		"timed_out":     !stable,
+		"waited_ms":     int(time.Since(start).Milliseconds()),
+		"stable_frames": frames,
+		"mutations":     m["mutations"],
+	}
+	if !stable {
+		res["still_changing"] = map[string]interface{}{
+			"mutating":           m["mutating"],
+			"shifting":           m["shifting"],
+			"running_animations": m["running_animations"],
+		}
+	}
+	return res, nil
+}
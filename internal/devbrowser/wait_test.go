@@ -0,0 +1,115 @@
+package devbrowser
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	cond, err := parseWaitCondition(map[string]interface{}{"state": "networkidle"})
+	if err != nil || cond != nil {
+		t.Fatalf("load-state wait should have no condition, got %v, %v", cond, err)
+	}
+
+	cond, err = parseWaitCondition(map[string]interface{}{"selector": ".toast", "state": "hidden"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond.Kind != "element" || cond.State != "hidden" {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+
+	cond, err = parseWaitCondition(map[string]interface{}{"ref": "e5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond.State != "visible" {
+		t.Fatalf("element state should default to visible, got %s", cond.State)
+	}
+
+	cond, err = parseWaitCondition(map[string]interface{}{"response": "**/api/save", "status": float64(200)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond.Kind != "response" || !matchAnyLinkPattern(cond.Response, "https://app.test/v1/api/save") || !cond.Status.matches(200) {
+		t.Fatalf("unexpected response condition %+v", cond)
+	}
+
+	cond, err = parseWaitCondition(map[string]interface{}{"url_matches": `/orders/\d+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cond.URL.MatchString("https://shop.test/orders/42") || cond.URL.MatchString("https://shop.test/orders/new") {
+		t.Fatalf("url_matches regex mismatch")
+	}
+
+	bad := []map[string]interface{}{
+		{"text": "Saved", "selector": "#x"},
+		{"selector": "#x", "state": "load"},
+		{"status": "200"},
+		{"url_matches": "("},
+		{"text": "Saved", "since": float64(3)},
+		{"console_match": "ready", "since_ms": float64(1000)},
+	}
+	for _, args := range bad {
+		if _, err := parseWaitCondition(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestStaleRefSatisfies(t *testing.T) {
+	stale := NewToolError(ErrRefStale, errors.New("ref e3 is stale"), nil)
+	missing := NewToolError(ErrRefNotFound, errors.New("ref e99 not found"), nil)
+	cases := []struct {
+		state string
+		err   error
+		want  bool
+	}{
+		{"detached", stale, true},
+		{"hidden", stale, true},
+		{"visible", stale, false},
+		{"detached", missing, false},
+		{"hidden", missing, false},
+		{"hidden", errors.New("evaluate failed"), false},
+	}
+	for _, tc := range cases {
+		if got := staleRefSatisfies(tc.state, tc.err); got != tc.want {
+			t.Fatalf("staleRefSatisfies(%s, %v) = %v, want %v", tc.state, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestFirstConsoleMatch(t *testing.T) {
+	logs := []ConsoleEntry{{ID: 4, Text: "booting"}, {ID: 5, Text: "app hydrated"}, {ID: 6, Text: "hydrated again"}}
+	entry, ok := firstConsoleMatch(logs, regexp.MustCompile("hydrated"))
+	if !ok || entry.ID != 5 {
+		t.Fatalf("expected entry 5, got %+v %v", entry, ok)
+	}
+	if _, ok := firstConsoleMatch(logs, regexp.MustCompile("^ready$")); ok {
+		t.Fatal("unexpected match")
+	}
+}
+
+func TestFirstResponseMatch(t *testing.T) {
+	cond, err := parseWaitCondition(map[string]interface{}{"response": "**/api/save", "status": "2xx", "since_ms": float64(150)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recent := []NetworkRequest{
+		{URL: "https://app.test/api/save", Status: 200, EndedMS: 100},
+		{URL: "https://app.test/api/save", Failed: true, EndedMS: 160},
+		{URL: "https://app.test/api/save", Status: 500, EndedMS: 170},
+		{URL: "https://app.test/api/load", Status: 200, EndedMS: 180},
+		{URL: "https://app.test/api/save", Status: 201, EndedMS: 190},
+	}
+	req, ok := firstResponseMatch(recent, cond)
+	if !ok || req.Status != 201 || req.EndedMS != 190 {
+		t.Fatalf("expected the 201 save after since_ms, got %+v %v", req, ok)
+	}
+	cond.SinceMS = 0
+	if req, _ := firstResponseMatch(recent, cond); req.EndedMS != 100 {
+		t.Fatalf("without since_ms the earliest recorded response should match, got %+v", req)
+	}
+}